@@ -2,11 +2,13 @@ package HVAC
 
 import (
 	"errors"
+	"math"
 )
 
 type Air struct {
 	Temperature float64 // in ºC
-	Humidity    float64 // in g/kg
+	Humidity    float64 // in kg/kg of dry air
+	Pressure    float64 // barometric, in Pa. Zero means NormalPressure
 }
 type PrintAir struct {
 	Temperature   float64
@@ -20,11 +22,22 @@ func (s *Air) VapourDensity() float64 {
 	return VaporDensity(s.Temperature)
 }
 func (s *Air) AirDensity() float64 {
-	return AirDensity(s.Temperature)
+	return AirDensityAt(s.Temperature, s.BarometricPressure())
+}
+
+// Returns the barometric pressure of the air (Pa), NormalPressure if it is not set
+func (s *Air) BarometricPressure() float64 {
+	if s.Pressure == 0 {
+		return NormalPressure
+	}
+	return s.Pressure
 }
 func (s *Air) SetHumidityRatio(HumidityRatio float64) error {
-	if HumidityRatio < 0 {
-		return errors.New("invalid Humidity Ratio")
+	if math.IsNaN(HumidityRatio) {
+		return &AirError{*s, "HumidityRatio", ErrNaN}
+	}
+	if HumidityRatio < 0 || HumidityRatio > 100 {
+		return &AirError{*s, "HumidityRatio", ErrOutOfRange}
 	}
 	s.Humidity = (HumidityRatio / 100) * s.VapourDensity() / s.AirDensity()
 	return nil
@@ -36,3 +49,138 @@ func (s *Air) HumidityRatio() float64 {
 func (s *Air) PartialPressure() float64 {
 	return PartialPressure(s.Temperature, s.HumidityRatio())
 }
+
+// Returns the humidity (kg/kg) of the saturated air at the same temperature and pressure
+func (s *Air) SaturationHumidity() float64 {
+	return SaturationHumidityAt(s.Temperature, s.BarometricPressure())
+}
+
+// Returns the dew point temperature (ºC).
+// Valid from -100 to 100 ºC, below -100 ºC (including completely dry air) -100 is returned
+func (s *Air) DewPoint() float64 {
+	p := s.PartialPressure()
+	return rootOrNearestBound(func(t float64) float64 { return VaporPressure(t) - p }, -100, 100, 1e-4)
+}
+
+// Returns the thermodynamic wet-bulb temperature (ºC).
+// The water is always treated as liquid, so below 0 ºC the result is approximate
+func (s *Air) WetBulb() float64 {
+	h := s.Enthalpy()
+	p := s.BarometricPressure()
+	return rootOrNearestBound(func(t float64) float64 {
+		xs := SaturationHumidityAt(t, p)
+		return h + (xs-s.Humidity)*WaterHeatCapacity(t)*t - AirEnthalpy(t, xs)
+	}, s.DewPoint(), s.Temperature, 1e-4)
+}
+
+// Returns the specific enthalpy (kJ/kg of dry air).
+// Valid from -100 to 200 ºC
+func (s *Air) Enthalpy() float64 {
+	return AirEnthalpy(s.Temperature, s.Humidity)
+}
+
+// Returns the specific volume (m3/kg of dry air)
+func (s *Air) SpecificVolume() float64 {
+	return (1 + s.Humidity*AirMolarMass/WaterMolarMass) / s.AirDensity()
+}
+
+// Returns the absolute humidity, i.e. the mass of water vapour in a cubic meter of air (g/m3)
+func (s *Air) AbsoluteHumidity() float64 {
+	return 1000 * s.Humidity * s.AirDensity()
+}
+
+// Returns the air state at normal pressure from the dry-bulb and thermodynamic wet-bulb temperatures
+func AirFromDryBulbWetBulb(DryBulb /* ºC */ float64, WetBulb /* ºC */ float64) (Air, error) {
+	return AirFromDryBulbWetBulbAt(DryBulb, WetBulb, NormalPressure)
+}
+
+// Same as AirFromDryBulbWetBulb, but at the selected barometric pressure. Zero pressure means NormalPressure
+func AirFromDryBulbWetBulbAt(DryBulb /* ºC */ float64, WetBulb /* ºC */ float64, Pressure /* Pa */ float64) (Air, error) {
+	if WetBulb > DryBulb {
+		return Air{}, errors.New("wet-bulb temperature is above dry-bulb temperature")
+	}
+	out := Air{Temperature: DryBulb, Pressure: Pressure}
+	if err := out.Validate(); err != nil {
+		return Air{}, err
+	}
+	xs := SaturationHumidityAt(WetBulb, out.BarometricPressure())
+	cw := WaterHeatCapacity(WetBulb)
+	x := (AirEnthalpy(WetBulb, xs) - xs*cw*WetBulb - DryAirHeatCapacity*DryBulb) / (VaporizationEnthalpyAt0 + WaterSteamHeatCapacity*DryBulb - cw*WetBulb)
+	if math.IsNaN(x) {
+		return Air{}, &AirError{out, "Humidity", ErrNaN}
+	}
+	if x < 0 {
+		return Air{}, errors.New("wet-bulb temperature is too low for the dry-bulb temperature")
+	}
+	out.Humidity = x
+	return out, nil
+}
+
+// Returns the air state at normal pressure from the specific enthalpy and relative humidity
+func AirFromEnthalpyRH(Enthalpy /* kJ/kg */ float64, HumidityRatio /* % */ float64) (Air, error) {
+	return AirFromEnthalpyRHAt(Enthalpy, HumidityRatio, NormalPressure)
+}
+
+// Same as AirFromEnthalpyRH, but at the selected barometric pressure
+func AirFromEnthalpyRHAt(Enthalpy /* kJ/kg */ float64, HumidityRatio /* % */ float64, Pressure /* Pa */ float64) (Air, error) {
+	if err := validateValue("HumidityRatio", HumidityRatio, 0, 100); err != nil {
+		return Air{}, err
+	}
+	state := func(t float64) Air {
+		out := Air{Temperature: t, Pressure: Pressure}
+		out.SetHumidityRatio(HumidityRatio)
+		return out
+	}
+	t := rootOrNearestBound(func(t float64) float64 {
+		a := state(t)
+		return a.Enthalpy() - Enthalpy
+	}, -100, 200, 1e-6)
+	out := state(t)
+	if math.Abs(out.Enthalpy()-Enthalpy) > 1e-3 {
+		return Air{}, errors.New("no air state with the selected enthalpy and humidity ratio")
+	}
+	return out, nil
+}
+
+// Returns the air state at normal pressure from the dry-bulb and dew point temperatures
+func AirFromDewPoint(DryBulb /* ºC */ float64, DewPoint /* ºC */ float64) (Air, error) {
+	return AirFromDewPointAt(DryBulb, DewPoint, NormalPressure)
+}
+
+// Same as AirFromDewPoint, but at the selected barometric pressure
+func AirFromDewPointAt(DryBulb /* ºC */ float64, DewPoint /* ºC */ float64, Pressure /* Pa */ float64) (Air, error) {
+	if DewPoint > DryBulb {
+		return Air{}, errors.New("dew point is above dry-bulb temperature")
+	}
+	out := Air{Temperature: DryBulb, Pressure: Pressure}
+	err := out.SetHumidityRatio(100 * VaporPressure(DewPoint) / VaporPressure(DryBulb))
+	return out, err
+}
+
+// Returns the state of two adiabatically mixed air streams.
+// Flowrates are in m3/h at the state of each stream. If the mixture is supersaturated,
+// the excess water stays in the air as fog: Mixed is saturated and Fog is the liquid water content (kg/kg)
+func MixAir(a Air, qa /* m3/h */ float64, b Air, qb /* m3/h */ float64) (Mixed Air, Fog float64 /* kg/kg */) {
+	ma := qa * a.AirDensity()
+	mb := qb * b.AirDensity()
+	if ma+mb <= 0 {
+		return a, 0
+	}
+	x := (ma*a.Humidity + mb*b.Humidity) / (ma + mb)
+	h := (ma*a.Enthalpy() + mb*b.Enthalpy()) / (ma + mb)
+	Mixed = Air{
+		Temperature: (h - VaporizationEnthalpyAt0*x) / (DryAirHeatCapacity + WaterSteamHeatCapacity*x),
+		Humidity:    x,
+		Pressure:    a.Pressure,
+	}
+	if xs := Mixed.SaturationHumidity(); x <= xs {
+		return Mixed, 0
+	}
+	p := Mixed.BarometricPressure()
+	Mixed.Temperature = rootOrNearestBound(func(t float64) float64 {
+		xs := SaturationHumidityAt(t, p)
+		return AirEnthalpy(t, xs) + (x-xs)*WaterHeatCapacity(t)*t - h
+	}, -100, Mixed.Temperature+50, 1e-6)
+	Mixed.Humidity = Mixed.SaturationHumidity()
+	return Mixed, x - Mixed.Humidity
+}
@@ -0,0 +1,200 @@
+package HVAC
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Antifreeze ids used in Extra.HeaterAntifreezeId and Extra.CoolerAntifreezeId.
+// The quantity (HeaterAntifreezeQty, CoolerAntifreezeQty) is the mass concentration in %
+const (
+	NoAntifreeze    = 0
+	EthyleneGlycol  = 1
+	PropyleneGlycol = 2
+)
+
+var ErrFluidFreezing = errors.New("heat transfer fluid freezes at the outdoor temperature")
+
+type (
+	// Heat transfer fluid of the water coils
+	Fluid interface {
+		Density(Temperature /* ºC */ float64) float64             // kg/m3
+		HeatCapacity(Temperature /* ºC */ float64) float64        // kJ/kg K
+		Viscosity(Temperature /* ºC */ float64) float64           // Pa s
+		ThermalConductivity(Temperature /* ºC */ float64) float64 // W/m K
+		FreezingPoint() float64                                   // ºC
+		Range() (Min float64, Max float64)                        // ºC
+	}
+	// Pure water, see WaterDensity and others
+	Water struct{}
+	// Water solution of glycol
+	Brine struct {
+		Name          string
+		Concentration float64 // mass %
+		properties    brineProperties
+	}
+	// Properties of the brine at 0 and 80 ºC. Viscosity is in mPa s
+	brineProperties struct {
+		concentration float64
+		freezingPoint float64
+		density       [2]float64
+		heatCapacity  [2]float64
+		viscosity     [2]float64
+		conductivity  [2]float64
+	}
+)
+
+var brineTables = map[uint64]struct {
+	name string
+	rows []brineProperties
+}{
+	EthyleneGlycol: {"Ethylene glycol", []brineProperties{
+		{10, -3.2, [2]float64{1016, 983}, [2]float64{3.94, 4.06}, [2]float64{2.2, .45}, [2]float64{.54, .61}},
+		{20, -7.8, [2]float64{1030, 995}, [2]float64{3.77, 3.94}, [2]float64{2.9, .58}, [2]float64{.50, .56}},
+		{30, -14.1, [2]float64{1045, 1006}, [2]float64{3.60, 3.81}, [2]float64{3.9, .72}, [2]float64{.46, .51}},
+		{40, -22.3, [2]float64{1060, 1017}, [2]float64{3.40, 3.66}, [2]float64{5.4, .90}, [2]float64{.42, .46}},
+		{50, -33.8, [2]float64{1074, 1028}, [2]float64{3.22, 3.51}, [2]float64{7.6, 1.15}, [2]float64{.38, .42}},
+	}},
+	PropyleneGlycol: {"Propylene glycol", []brineProperties{
+		{10, -3.3, [2]float64{1010, 980}, [2]float64{4.05, 4.13}, [2]float64{2.6, .45}, [2]float64{.53, .60}},
+		{20, -7.1, [2]float64{1020, 987}, [2]float64{3.96, 4.08}, [2]float64{4.0, .58}, [2]float64{.49, .55}},
+		{30, -12.7, [2]float64{1030, 993}, [2]float64{3.84, 4.00}, [2]float64{6.2, .75}, [2]float64{.45, .50}},
+		{40, -21.1, [2]float64{1040, 998}, [2]float64{3.70, 3.90}, [2]float64{10, .95}, [2]float64{.41, .45}},
+		{50, -33.5, [2]float64{1048, 1001}, [2]float64{3.54, 3.78}, [2]float64{17, 1.25}, [2]float64{.37, .41}},
+	}},
+}
+
+// Returns the heat transfer fluid by the antifreeze id and concentration (mass %).
+// Zero id or concentration means pure water
+func NewFluid(AntifreezeId uint64, AntifreezeQty uint64) (Fluid, error) {
+	if AntifreezeId == NoAntifreeze || AntifreezeQty == 0 {
+		return Water{}, nil
+	}
+	table, ok := brineTables[AntifreezeId]
+	if !ok {
+		return nil, fmt.Errorf("unknown antifreeze id %v", AntifreezeId)
+	}
+	c := float64(AntifreezeQty)
+	rows := table.rows
+	if c < rows[0].concentration || c > rows[len(rows)-1].concentration {
+		return nil, fmt.Errorf("%v concentration %v %%: %w", table.name, AntifreezeQty, ErrOutOfRange)
+	}
+	i := 1
+	for i < len(rows)-1 && rows[i].concentration < c {
+		i++
+	}
+	a, b := rows[i-1], rows[i]
+	k := (c - a.concentration) / (b.concentration - a.concentration)
+	mix := func(x, y [2]float64) [2]float64 {
+		return [2]float64{x[0] + k*(y[0]-x[0]), x[1] + k*(y[1]-x[1])}
+	}
+	return Brine{
+		Name:          table.name,
+		Concentration: c,
+		properties: brineProperties{
+			concentration: c,
+			freezingPoint: a.freezingPoint + k*(b.freezingPoint-a.freezingPoint),
+			density:       mix(a.density, b.density),
+			heatCapacity:  mix(a.heatCapacity, b.heatCapacity),
+			viscosity:     [2]float64{math.Exp(math.Log(a.viscosity[0]) + k*math.Log(b.viscosity[0]/a.viscosity[0])), math.Exp(math.Log(a.viscosity[1]) + k*math.Log(b.viscosity[1]/a.viscosity[1]))},
+			conductivity:  mix(a.conductivity, b.conductivity),
+		},
+	}, nil
+}
+
+// Returns the fluid of the heater
+func (s Extra) HeaterFluid() (Fluid, error) {
+	return NewFluid(s.HeaterAntifreezeId, s.HeaterAntifreezeQty)
+}
+
+// Returns the fluid of the cooler
+func (s Extra) CoolerFluid() (Fluid, error) {
+	return NewFluid(s.CoolerAntifreezeId, s.CoolerAntifreezeQty)
+}
+
+func (Water) Density(Temperature float64) float64      { return WaterDensity(Temperature) }
+func (Water) HeatCapacity(Temperature float64) float64 { return WaterHeatCapacity(Temperature) }
+func (Water) Viscosity(Temperature float64) float64    { return WaterViscosity(Temperature) }
+func (Water) ThermalConductivity(Temperature float64) float64 {
+	return WaterThermalConductivity(Temperature)
+}
+func (Water) FreezingPoint() float64    { return 0 }
+func (Water) Range() (float64, float64) { return WaterMinTemperature, WaterMaxTemperature }
+
+// Linear interpolation between the values at 0 and 80 ºC
+func brineLinear(v [2]float64, Temperature float64) float64 {
+	return v[0] + (v[1]-v[0])*Temperature/80
+}
+
+func (s Brine) Density(Temperature float64) float64 {
+	return brineLinear(s.properties.density, Temperature)
+}
+func (s Brine) HeatCapacity(Temperature float64) float64 {
+	return brineLinear(s.properties.heatCapacity, Temperature)
+}
+
+// Viscosity follows the Andrade equation ln(μ) = A + B/T through the values at 0 and 80 ºC
+func (s Brine) Viscosity(Temperature float64) float64 {
+	t0, t80 := CelsiusToKelvinDifference, 80+CelsiusToKelvinDifference
+	v := s.properties.viscosity
+	b := math.Log(v[0]/v[1]) / (1/t0 - 1/t80)
+	return v[0] * math.Exp(b*(1/(Temperature+CelsiusToKelvinDifference)-1/t0)) / 1000
+}
+func (s Brine) ThermalConductivity(Temperature float64) float64 {
+	return brineLinear(s.properties.conductivity, Temperature)
+}
+func (s Brine) FreezingPoint() float64 {
+	return s.properties.freezingPoint
+}
+func (s Brine) Range() (float64, float64) {
+	return s.properties.freezingPoint, WaterMaxTemperature
+}
+
+// Returns the power that the fluid flow gives off when cooled from InletTemperature to OutgoingTemperature. See WaterHeatPower
+func FluidHeatPower(Fluid Fluid, InletTemperature /* ºC */ float64, OutgoingTemperature /* ºC */ float64, VolumetricFlowrate /* dm3/h */ float64) float64 /* kW */ {
+	return math.Abs(OutgoingTemperature-InletTemperature) * VolumetricFlowrate * Fluid.Density(InletTemperature) * Fluid.HeatCapacity(InletTemperature) / (SecondsInHour * 1000)
+}
+
+// Returns the temperature difference of the in- and outgoing fluid flow. See WaterHeatTemperature
+func FluidHeatTemperature(Fluid Fluid, HeatingPower /* kW */ float64, VolumetricFlowrate /* dm3/h */ float64, InletTemperature /* ºC */ float64) float64 /* ºC */ {
+	return HeatingPower / (VolumetricFlowrate * Fluid.Density(InletTemperature) * Fluid.HeatCapacity(InletTemperature) / (SecondsInHour * 1000))
+}
+
+// Returns the fluid volumetric flowrate required to produce selected power. See WaterHeatVolumetricFlowRate
+func FluidHeatVolumetricFlowRate(Fluid Fluid, HeatingPower /* kW */ float64, InletTemperature /* ºC */ float64, OutgoingTemperature /* ºC */ float64) float64 /* dm3/h */ {
+	return HeatingPower / (math.Abs(OutgoingTemperature-InletTemperature) * Fluid.Density(InletTemperature) * Fluid.HeatCapacity(InletTemperature) / (SecondsInHour * 1000))
+}
+
+// Checks that the fluid does not freeze at the outdoor temperature
+func CheckFreezeProtection(Fluid Fluid, OutdoorTemperature /* ºC */ float64) error {
+	if Fluid.FreezingPoint() >= OutdoorTemperature {
+		return fmt.Errorf("%w: freezing point %.1f ºC, outdoor %.1f ºC", ErrFluidFreezing, Fluid.FreezingPoint(), OutdoorTemperature)
+	}
+	return nil
+}
+
+// Checks the heater and cooler fluids against the winter outdoor temperature.
+// Coils without water temperatures set are not checked
+func (s UnitTask) CheckFreezeProtection() error {
+	extra := Extra(s.Extra)
+	if extra.HeatedWaterInletTemperature != 0 || extra.HeatedWaterOutgoingTemperature != 0 {
+		fluid, err := extra.HeaterFluid()
+		if err != nil {
+			return err
+		}
+		if err = CheckFreezeProtection(fluid, s.Winter.Outdoor.Temperature); err != nil {
+			return fmt.Errorf("heater: %w", err)
+		}
+	}
+	if extra.ChilledWaterInletTemperature != 0 || extra.ChilledWaterOutgoingTemperature != 0 {
+		fluid, err := extra.CoolerFluid()
+		if err != nil {
+			return err
+		}
+		if err = CheckFreezeProtection(fluid, s.Winter.Outdoor.Temperature); err != nil {
+			return fmt.Errorf("cooler: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package HVAC
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+)
+
+type (
+	// Air treatment process drawn on the psychrometric chart
+	ChartProcess struct {
+		Name   string
+		Color  string
+		Dashed bool
+		Points []Air
+	}
+	// Mollier h-x chart: humidity on the horizontal axis, dry-bulb temperature on the vertical one
+	chart struct {
+		width, height            float64
+		minT, maxT, maxX         float64
+		left, right, top, bottom float64
+		pressure                 float64
+		builder                  strings.Builder
+	}
+	// Heater, cooler or humidifier result with Present set if the unit has the section
+	chartSection struct {
+		Result  HeaterResult
+		Present bool
+	}
+)
+
+// Returns the Mollier (h-x) chart in svg format with relative humidity curves, isenthalps and the selected processes.
+// The chart covers -20..40 ºC and 0..20 g/kg and is extended to fit every process point
+func PsychrometricChart(Width uint64, Height uint64, Processes ...ChartProcess) string {
+	c := chart{
+		width:    float64(Width),
+		height:   float64(Height),
+		minT:     -20,
+		maxT:     40,
+		maxX:     20,
+		left:     50,
+		right:    20,
+		top:      20,
+		bottom:   40,
+		pressure: NormalPressure,
+	}
+	for _, process := range Processes {
+		for _, point := range process.Points {
+			c.minT = math.Min(c.minT, 5*math.Floor(point.Temperature/5-1))
+			c.maxT = math.Max(c.maxT, 5*math.Ceil(point.Temperature/5+1))
+			c.maxX = math.Max(c.maxX, 2*math.Ceil(point.Humidity*500+1))
+			c.pressure = point.BarometricPressure()
+		}
+	}
+	fmt.Fprintf(&c.builder, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="10">`, Width, Height, Width, Height)
+	fmt.Fprintf(&c.builder, `<defs><clipPath id="area"><rect x="%.1f" y="%.1f" width="%.1f" height="%.1f"/></clipPath></defs>`, c.left, c.top, c.width-c.left-c.right, c.height-c.top-c.bottom)
+	fmt.Fprintf(&c.builder, `<rect x="0" y="0" width="%d" height="%d" fill="white"/>`, Width, Height)
+	c.grid()
+	c.isenthalps()
+	c.humidityRatios()
+	for _, process := range Processes {
+		c.process(process)
+	}
+	fmt.Fprintf(&c.builder, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="none" stroke="black"/>`, c.left, c.top, c.width-c.left-c.right, c.height-c.top-c.bottom)
+	c.builder.WriteString(`</svg>`)
+	return c.builder.String()
+}
+
+// Returns the Summer and Winter processes of the unit (Outdoor → heat recovery → heaters/coolers → Supply
+// and Indoor → heat recovery → Exhaust) on the Mollier chart. Only the lines and the sections of the unit are drawn.
+// The result is meant for UnitDescription.Plot
+func (s UnitDescription) ProcessChart(Width uint64, Height uint64) string {
+	supply := s.Task.SectionList.SupplyLine || s.IsSupplyBlower
+	exhaust := s.Task.SectionList.ExhaustLine || s.IsExhaustBlower
+	preheater := s.IsHeatedWaterPreHeater || s.IsElectricHeaterPreHeater
+	cooler := s.IsChilledWater || s.IsDirectExpansion
+	heater := s.IsHeatedWater || s.IsElectricHeater
+	humidifier := s.IsMediaHumidifier || s.IsSteamHumidifier
+	summer := seasonProcess(s.Result.Summer, supply, exhaust, s.HeatRecovery.Summer,
+		chartSection{s.PreHeater.Summer, preheater}, chartSection{s.Cooler.Summer, cooler}, chartSection{s.Heater.Summer, heater}, chartSection{s.Humidifier.Summer, humidifier})
+	winter := seasonProcess(s.Result.Winter, supply, exhaust, s.HeatRecovery.Winter,
+		chartSection{s.PreHeater.Winter, preheater}, chartSection{s.Cooler.Winter, cooler}, chartSection{s.Heater.Winter, heater}, chartSection{s.Humidifier.Winter, humidifier})
+	return PsychrometricChart(Width, Height,
+		ChartProcess{Name: "Summer", Color: "#d62728", Points: summer[0]},
+		ChartProcess{Color: "#d62728", Dashed: true, Points: summer[1]},
+		ChartProcess{Name: "Winter", Color: "#1f77b4", Points: winter[0]},
+		ChartProcess{Color: "#1f77b4", Dashed: true, Points: winter[1]},
+	)
+}
+
+// Returns the supply and exhaust air states of one season in the order of the sections.
+// Supply and Exhaust tell whether the unit has the line, the first section is the preheater
+func seasonProcess(Result UnitResult, Supply bool, Exhaust bool, HeatRecovery HeatRecoveryResult, Sections ...chartSection) (out [2][]Air) {
+	add := func(line int, a Air) {
+		if len(out[line]) > 0 && out[line][len(out[line])-1] == a {
+			return
+		}
+		out[line] = append(out[line], a)
+	}
+	changes := func(section chartSection) bool {
+		return section.Present && section.Result.Inlet != section.Result.Outgoing
+	}
+	recovery := HeatRecovery.Supply != HeatRecovery.Outside
+	if Supply {
+		add(0, Result.Outdoor)
+		if changes(Sections[0]) {
+			add(0, Sections[0].Result.Outgoing)
+		}
+		if recovery {
+			add(0, HeatRecovery.Supply)
+		}
+		for _, section := range Sections[1:] {
+			if changes(section) {
+				add(0, section.Result.Outgoing)
+			}
+		}
+		add(0, Result.Supply)
+	}
+	if Exhaust {
+		add(1, Result.Indoor)
+		if recovery {
+			add(1, HeatRecovery.Exhaust)
+		}
+		add(1, Result.Exhaust)
+	}
+	return
+}
+
+func (c *chart) point(Temperature float64, Humidity /* kg/kg */ float64) (float64, float64) {
+	return c.left + (c.width-c.left-c.right)*Humidity*1000/c.maxX,
+		c.height - c.bottom - (c.height-c.top-c.bottom)*(Temperature-c.minT)/(c.maxT-c.minT)
+}
+
+func (c *chart) polyline(Points [][2]float64, Style string) {
+	c.builder.WriteString(`<polyline clip-path="url(#area)" fill="none" points="`)
+	for _, p := range Points {
+		x, y := c.point(p[0], p[1])
+		fmt.Fprintf(&c.builder, "%.1f,%.1f ", x, y)
+	}
+	fmt.Fprintf(&c.builder, `" %s/>`, Style)
+}
+
+func (c *chart) text(x float64, y float64, Anchor string, Color string, Text string) {
+	fmt.Fprintf(&c.builder, `<text x="%.1f" y="%.1f" text-anchor="%s" fill="%s">%s</text>`, x, y, Anchor, html.EscapeString(Color), html.EscapeString(Text))
+}
+
+// Isotherms and lines of constant humidity with axis labels
+func (c *chart) grid() {
+	for t := c.minT; t <= c.maxT; t += 5 {
+		c.polyline([][2]float64{{t, 0}, {t, c.maxX / 1000}}, `stroke="#ddd"`)
+		x, y := c.point(t, 0)
+		c.text(x-4, y+3, "end", "black", fmt.Sprintf("%g", t))
+	}
+	for h := 0.; h <= c.maxX; h += 2 {
+		c.polyline([][2]float64{{c.minT, h / 1000}, {c.maxT, h / 1000}}, `stroke="#ddd"`)
+		x, y := c.point(c.minT, h/1000)
+		c.text(x, y+14, "middle", "black", fmt.Sprintf("%g", h))
+	}
+	c.text(c.left-4, c.top-6, "end", "black", "t, ºC")
+	c.text(c.width-c.right, c.height-4, "end", "black", "x, g/kg")
+}
+
+// Lines of constant specific enthalpy every 10 kJ/kg
+func (c *chart) isenthalps() {
+	low := 10 * math.Floor(AirEnthalpy(c.minT, 0)/10)
+	high := 10 * math.Ceil(AirEnthalpy(c.maxT, c.maxX/1000)/10)
+	for h := low; h <= high; h += 10 {
+		var points [][2]float64
+		for x := 0.; x <= c.maxX/1000; x += c.maxX / 10000 {
+			points = append(points, [2]float64{(h - VaporizationEnthalpyAt0*x) / (DryAirHeatCapacity + WaterSteamHeatCapacity*x), x})
+		}
+		c.polyline(points, `stroke="#9c9" stroke-width="0.5"`)
+		if t := h / DryAirHeatCapacity; t > c.minT && t < c.maxT {
+			x, y := c.point(t, 0)
+			c.text(x+2, y-2, "start", "#393", fmt.Sprintf("%g", h))
+		}
+	}
+}
+
+// Relative humidity curves every 10 %
+func (c *chart) humidityRatios() {
+	for rh := 10.; rh <= 100; rh += 10 {
+		var points [][2]float64
+		for t := c.minT; t <= c.maxT; t += .5 {
+			points = append(points, [2]float64{t, rh / 100 * SaturationHumidityAt(t, c.pressure)})
+		}
+		style := `stroke="#69c" stroke-width="0.5"`
+		if rh == 100 {
+			style = `stroke="#36c" stroke-width="1.5"`
+		}
+		c.polyline(points, style)
+		last := points[0]
+		for _, p := range points {
+			if p[1]*1000 <= c.maxX {
+				last = p
+			}
+		}
+		x, y := c.point(last[0], last[1])
+		c.text(x, y-3, "end", "#36c", fmt.Sprintf("%g%%", rh))
+	}
+}
+
+func (c *chart) process(Process ChartProcess) {
+	if len(Process.Points) == 0 {
+		return
+	}
+	color := html.EscapeString(Process.Color)
+	style := fmt.Sprintf(`stroke="%s" stroke-width="2"`, color)
+	if Process.Dashed {
+		style += ` stroke-dasharray="6,3"`
+	}
+	points := make([][2]float64, len(Process.Points))
+	for i, a := range Process.Points {
+		points[i] = [2]float64{a.Temperature, a.Humidity}
+	}
+	c.polyline(points, style)
+	for _, p := range points {
+		x, y := c.point(p[0], p[1])
+		fmt.Fprintf(&c.builder, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"/>`, x, y, color)
+	}
+	if Process.Name != "" {
+		x, y := c.point(points[0][0], points[0][1])
+		c.text(x+5, y-5, "start", Process.Color, Process.Name)
+	}
+}
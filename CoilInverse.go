@@ -0,0 +1,75 @@
+package HVAC
+
+import (
+	"fmt"
+	"math"
+)
+
+// Returns the inlet water temperature at which the water flow (Q) gives off the selected power while cooled down
+// to OutgoingTemperature. It is the inverse of WaterHeatPower; for cooling the power is negative
+func WaterHeatInletTemperature[T anyFloat](HeatingPower /* kW */ T, VolumetricFlowrate /* dm3/h */ T, OutgoingTemperature /* ºC */ T) (T /* ºC */, error) {
+	return FindRoot(func(t T) T {
+		power := WaterHeatPower(t, OutgoingTemperature, VolumetricFlowrate)
+		if t < OutgoingTemperature {
+			power = -power
+		}
+		return power - HeatingPower
+	}, WaterMinTemperature, WaterMaxTemperature, 1e-6)
+}
+
+// Returns the inlet water temperature that heats (or cools) the air up to Task.Target at the selected water flowrate
+func (s WaterCoil) RequiredWaterInletTemperature(Task HeaterTask, Fluid Fluid, WaterVolumetricFlowrate /* dm3/h */ float64) (float64 /* ºC */, error) {
+	min, max := Fluid.Range()
+	var err error
+	t, root_err := FindRoot(func(t float64) float64 {
+		rating, e := s.Rate(Task.Inlet, float64(Task.VolumetricFlowrate), Fluid, t, WaterVolumetricFlowrate)
+		if e != nil && err == nil {
+			err = e
+		}
+		return rating.Outgoing.Temperature - Task.Target
+	}, min, max, 1e-4)
+	if err != nil {
+		return math.NaN(), err
+	}
+	if root_err != nil {
+		return math.NaN(), fmt.Errorf("target is out of reach with water from %v to %v ºC: %w", min, max, root_err)
+	}
+	return t, nil
+}
+
+// Returns the outgoing air temperature at the selected water inlet temperature and flowrate.
+// For heaters it is the maximum supply temperature, for coolers the minimum one
+func (s WaterCoil) OutgoingTemperature(Inlet Air, VolumetricFlowrate /* m3/h */ float64, Fluid Fluid, WaterInletTemperature /* ºC */ float64, WaterVolumetricFlowrate /* dm3/h */ float64) (float64 /* ºC */, error) {
+	rating, err := s.Rate(Inlet, VolumetricFlowrate, Fluid, WaterInletTemperature, WaterVolumetricFlowrate)
+	return rating.Outgoing.Temperature, err
+}
+
+// Returns the coil result at the part of the water flowrate that gives the design water temperatures.
+// It shows how the coil behaves when the water flow is reduced (Fraction < 1) or increased
+func (s WaterCoil) PartLoad(Inlet Air, VolumetricFlowrate /* m3/h */ float64, Fluid Fluid, WaterInletTemperature /* ºC */ float64, WaterOutgoingTemperature /* ºC */ float64, Fraction float64) (CoilRating, error) {
+	design, err := s.RateAtWaterTemperatures(Inlet, VolumetricFlowrate, Fluid, WaterInletTemperature, WaterOutgoingTemperature)
+	if err != nil {
+		return design, err
+	}
+	return s.Rate(Inlet, VolumetricFlowrate, Fluid, WaterInletTemperature, Fraction*design.WaterVolumetricFlowrate)
+}
+
+// Returns the water flowrate (dm3/h) that heats (or cools) the air up to Task.Target at the selected water inlet temperature
+// If several flowrates reach the target, the highest one is taken as in RateAtWaterTemperatures
+func (s WaterCoil) RequiredWaterFlowrate(Task HeaterTask, Fluid Fluid, WaterInletTemperature /* ºC */ float64) (float64 /* dm3/h */, error) {
+	var err error
+	q, root_err := highestRoot(func(q float64) float64 {
+		rating, e := s.Rate(Task.Inlet, float64(Task.VolumetricFlowrate), Fluid, WaterInletTemperature, math.Exp(q))
+		if e != nil && err == nil {
+			err = e
+		}
+		return math.Abs(rating.Outgoing.Temperature-Task.Inlet.Temperature) - math.Abs(Task.Target-Task.Inlet.Temperature)
+	}, math.Log(1e-3), math.Log(1e7), 100, 1e-6)
+	if err != nil {
+		return math.NaN(), err
+	}
+	if root_err != nil {
+		return math.NaN(), fmt.Errorf("target is out of reach with water at %v ºC: %w", WaterInletTemperature, root_err)
+	}
+	return math.Exp(q), nil
+}
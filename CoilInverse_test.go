@@ -0,0 +1,141 @@
+package HVAC
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+var testCoil = WaterCoil{
+	Rows:              2,
+	Tubes:             20,
+	Circuits:          10,
+	TubeLength:        1,
+	TubeOuterDiameter: 12.7,
+	TubeInnerDiameter: 11.7,
+	TubePitch:         31.75,
+	RowPitch:          27.5,
+	FinPitch:          2.5,
+	FinThickness:      .12,
+}
+
+func TestWaterHeatInletTemperature(t *testing.T) {
+	tests := []struct {
+		name          string
+		inlet, outlet float64
+	}{
+		{"heating", 70, 50},
+		{"cooling", 7, 12},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			power := WaterHeatPower(test.inlet, test.outlet, 1000.)
+			if test.inlet < test.outlet {
+				power = -power
+			}
+			got, err := WaterHeatInletTemperature(power, 1000., test.outlet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got-test.inlet) > 1e-4 {
+				t.Errorf("inlet %v ºC, want %v ºC", got, test.inlet)
+			}
+		})
+	}
+}
+
+func TestCoilInverseRoundTrip(t *testing.T) {
+	summer := Air{Temperature: 28}
+	summer.SetHumidityRatio(50)
+	tests := []struct {
+		name  string
+		inlet Air
+		water float64 // ºC
+		flow  float64 // dm3/h
+	}{
+		{"heating", Air{Temperature: -20, Humidity: .0005}, 70, 1500},
+		{"dry cooling", Air{Temperature: 28, Humidity: .005}, 12, 2000},
+		{"wet cooling", summer, 7, 2000},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rating, err := testCoil.Rate(test.inlet, 3000, Water{}, test.water, test.flow)
+			if err != nil {
+				t.Fatal(err)
+			}
+			task := HeaterTask{Inlet: test.inlet, Target: rating.Outgoing.Temperature, VolumetricFlowrate: 3000}
+
+			water, err := testCoil.RequiredWaterInletTemperature(task, Water{}, test.flow)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(water-test.water) > 1e-2 {
+				t.Errorf("water inlet %v ºC, want %v ºC", water, test.water)
+			}
+
+			flow, err := testCoil.RequiredWaterFlowrate(task, Water{}, test.water)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(flow-test.flow) > 1e-3*test.flow {
+				t.Errorf("water flowrate %v dm3/h, want %v dm3/h", flow, test.flow)
+			}
+
+			design, err := testCoil.RateAtWaterTemperatures(test.inlet, 3000, Water{}, test.water, rating.WaterOutgoingTemperature)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(design.WaterVolumetricFlowrate-test.flow) > 1e-3*test.flow {
+				t.Errorf("design water flowrate %v dm3/h, want %v dm3/h", design.WaterVolumetricFlowrate, test.flow)
+			}
+		})
+	}
+}
+
+// Chilled water 7/12 ºC: the dry laminar, the wet transitional and the wet turbulent coil
+// all reach 12 ºC at about 550, 1290 and 1620 dm3/h, the most powerful one is the design point
+func TestChilledWaterCoilSelect(t *testing.T) {
+	inlet := Air{Temperature: 28}
+	inlet.SetHumidityRatio(50)
+	design, err := testCoil.RateAtWaterTemperatures(inlet, 3000, Water{}, 7, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if design.WaterVolumetricFlowrate < 1500 || !design.Wet {
+		t.Errorf("design water flowrate %v dm3/h (wet %v), want the wet coil above 1500 dm3/h", design.WaterVolumetricFlowrate, design.Wet)
+	}
+	for _, flowrate := range []float64{553, 1290, design.WaterVolumetricFlowrate / 2} {
+		rating, err := testCoil.Rate(inlet, 3000, Water{}, 7, flowrate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rating.Power < design.Power {
+			t.Errorf("%v dm3/h gives %v kW, more than %v kW at the design point", flowrate, -rating.Power, -design.Power)
+		}
+	}
+	result, err := testCoil.Select(HeaterTask{Inlet: inlet, Target: 14, VolumetricFlowrate: 3000}, Water{}, 7, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result.Capacity+design.Power) > 1e-6 || !result.LowCapacity {
+		t.Errorf("capacity %v kW (low %v), want %v kW short of the target", result.Capacity, result.LowCapacity, -design.Power)
+	}
+	result, err = testCoil.Select(HeaterTask{Inlet: inlet, Target: 23, VolumetricFlowrate: 3000}, Water{}, 7, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result.Outgoing.Temperature-23) > 1e-3 || result.LowCapacity {
+		t.Errorf("outgoing %v ºC (low %v), want 23 ºC", result.Outgoing.Temperature, result.LowCapacity)
+	}
+}
+
+func TestCoilInverseOutOfReach(t *testing.T) {
+	inlet := Air{Temperature: -20, Humidity: .0005}
+	if _, err := testCoil.RateAtWaterTemperatures(inlet, 3000, Water{}, 80, 79.999); !errors.Is(err, ErrNoRoot) {
+		t.Errorf("water 80 → 79.999 ºC: error %v, want ErrNoRoot", err)
+	}
+	task := HeaterTask{Inlet: inlet, Target: 90, VolumetricFlowrate: 3000}
+	if _, err := testCoil.RequiredWaterFlowrate(task, Water{}, 70); !errors.Is(err, ErrNoRoot) {
+		t.Errorf("target above the water temperature: error %v, want ErrNoRoot", err)
+	}
+}
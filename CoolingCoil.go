@@ -0,0 +1,84 @@
+package HVAC
+
+import "errors"
+
+type (
+	// Cooling coil process based on the apparatus dew point (ADP) and the bypass factor (BF).
+	// The outgoing air is the mix of the bypassed inlet air and the saturated air at ADP
+	CoolingCoilResult struct {
+		Inlet                        Air
+		Outgoing                     Air
+		VolumetricFlowrate           float64 // m3/h
+		ApparatusDewPoint            float64 // ºC
+		BypassFactor                 float64
+		SensiblePower                float64 // kW
+		LatentPower                  float64 // kW
+		Power                        float64 // kW, total
+		SensibleHeatRatio            float64
+		CondensateMassFlowrate       float64 // kg/h
+		CondensateVolumetricFlowrate float64 // l/h
+	}
+)
+
+// Returns the cooling coil process for the selected apparatus dew point and bypass factor
+func CoolingCoilProcess(Inlet Air, ApparatusDewPoint /* ºC */ float64, BypassFactor float64, VolumetricFlowrate /* m3/h */ float64) (out CoolingCoilResult, err error) {
+	if err = Inlet.Validate(); err != nil {
+		return
+	}
+	if BypassFactor < 0 || BypassFactor > 1 {
+		return out, errors.New("invalid bypass factor")
+	}
+	if ApparatusDewPoint > Inlet.Temperature {
+		return out, errors.New("apparatus dew point is above inlet temperature")
+	}
+	out = CoolingCoilResult{
+		Inlet:              Inlet,
+		Outgoing:           Inlet,
+		VolumetricFlowrate: VolumetricFlowrate,
+		ApparatusDewPoint:  ApparatusDewPoint,
+		BypassFactor:       BypassFactor,
+	}
+	out.Outgoing.Temperature = ApparatusDewPoint + BypassFactor*(Inlet.Temperature-ApparatusDewPoint)
+	if adp := SaturationHumidityAt(ApparatusDewPoint, Inlet.BarometricPressure()); Inlet.Humidity > adp {
+		out.Outgoing.Humidity = adp + BypassFactor*(Inlet.Humidity-adp)
+	}
+	mass_flowrate := VolumetricFlowrate * Inlet.AirDensity() / SecondsInHour // kg/s of dry air
+	out.CondensateMassFlowrate = mass_flowrate * (Inlet.Humidity - out.Outgoing.Humidity) * SecondsInHour
+	out.CondensateVolumetricFlowrate = 1000 * out.CondensateMassFlowrate / WaterDensity(ApparatusDewPoint)
+	out.Power = mass_flowrate*(Inlet.Enthalpy()-out.Outgoing.Enthalpy()) -
+		out.CondensateMassFlowrate/SecondsInHour*WaterHeatCapacity(ApparatusDewPoint)*ApparatusDewPoint
+	out.SensiblePower = AirHeatPowerAt(Inlet.Temperature, Inlet.Humidity, out.Outgoing.Temperature, VolumetricFlowrate, Inlet.BarometricPressure())
+	out.LatentPower = out.Power - out.SensiblePower
+	if out.Power > 0 {
+		out.SensibleHeatRatio = out.SensiblePower / out.Power
+	}
+	return
+}
+
+// Returns the cooling coil process that cools the air down to Task.Target with the selected apparatus dew point.
+// The bypass factor is derived from the target temperature
+func CoolingCoilByTarget(Task HeaterTask, ApparatusDewPoint /* ºC */ float64) (CoolingCoilResult, error) {
+	if Task.Target < ApparatusDewPoint {
+		return CoolingCoilResult{}, errors.New("target temperature is below apparatus dew point")
+	}
+	bypass := 1.
+	if Task.Target < Task.Inlet.Temperature {
+		bypass = (Task.Target - ApparatusDewPoint) / (Task.Inlet.Temperature - ApparatusDewPoint)
+	}
+	return CoolingCoilProcess(Task.Inlet, ApparatusDewPoint, bypass, float64(Task.VolumetricFlowrate))
+}
+
+// Returns the cooler result. The chilled water flowrate is taken from the water temperatures in Extra
+func (s CoolingCoilResult) HeaterResult(Extra Extra) HeaterResult {
+	out := HeaterResult{
+		Inlet:              s.Inlet,
+		Outgoing:           s.Outgoing,
+		VolumetricFlowrate: s.VolumetricFlowrate,
+		Capacity:           s.Power,
+		Power:              s.Power,
+	}
+	if Extra.ChilledWaterInletTemperature != Extra.ChilledWaterOutgoingTemperature {
+		out.WaterVolumetricFlowrate = WaterHeatVolumetricFlowRate(s.Power, Extra.ChilledWaterInletTemperature, Extra.ChilledWaterOutgoingTemperature)
+	}
+	return out
+}
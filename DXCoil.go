@@ -0,0 +1,391 @@
+package HVAC
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	EvaporatingHeatTransfer             = 3000 // W/m2 K, boiling refrigerant in the tubes
+	CondensingTemperatureDifference     = 15   // K, condensing temperature above the outdoor one
+	MinEvaporatingTemperature           = -30  // ºC
+	RefrigerantSubcooling               = 5    // K
+	DefaultEvaporatorSuperheat          = 5    // K
+	DefaultCondensingCapacityFactor     = .03  // per K of the evaporating temperature
+	DefaultCondensingPowerFactor        = .01  // per K of the evaporating temperature
+	DefaultRatingEvaporatingTemperature = 7    // ºC
+)
+
+type (
+	// Simplified saturation table of the refrigerant. For zeotropic blends the values are at the dew point
+	Refrigerant struct {
+		Name               string
+		Glide              float64 // K
+		LiquidHeatCapacity float64 // kJ/kg K
+		VaporHeatCapacity  float64 // kJ/kg K
+		table              []refrigerantPoint
+	}
+	refrigerantPoint struct {
+		temperature float64 // ºC
+		pressure    float64 // kPa, absolute
+		latentHeat  float64 // kJ/kg
+	}
+	// Evaporator with the geometry of the water coil, Coil.Circuits are the refrigerant circuits.
+	// The coil is not embedded, so the water side calculations are not the evaporator ones
+	DXCoil struct {
+		Coil        WaterCoil
+		Refrigerant Refrigerant
+		Superheat   float64 // K. Zero means DefaultEvaporatorSuperheat
+	}
+	// Capacity and consumed power of the condensing unit at the outdoor temperature and RatingEvaporatingTemperature
+	CondensingUnitPoint struct {
+		OutdoorTemperature float64 // ºC
+		Capacity           float64 // kW
+		Power              float64 // kW
+	}
+	CondensingUnit struct {
+		LongName                     string
+		ShortName                    string
+		RatingEvaporatingTemperature float64 // ºC. Zero means DefaultRatingEvaporatingTemperature
+		CapacityFactor               float64 // per K. Zero means DefaultCondensingCapacityFactor
+		PowerFactor                  float64 // per K. Zero means DefaultCondensingPowerFactor
+		Curve                        []CondensingUnitPoint
+	}
+	// Result of the evaporator matched with the condensing unit. EER is the cooling power to the consumed one
+	DXResult struct {
+		HeaterResult
+		OutdoorTemperature      float64 // ºC
+		EvaporatingTemperature  float64 // ºC
+		EvaporatingPressure     float64 // kPa
+		CondensingTemperature   float64 // ºC
+		RefrigerantMassFlowrate float64 // kg/h
+		CondensateMassFlowrate  float64 // kg/h
+		ElectricPower           float64 // kW
+		EER                     float64
+	}
+	DXResult2 struct {
+		LongName  string
+		ShortName string
+		Length    uint64
+		Summer    DXResult
+		Winter    DXResult
+	}
+)
+
+var (
+	R410A = Refrigerant{Name: "R410A", Glide: .1, LiquidHeatCapacity: 1.6, VaporHeatCapacity: .9, table: []refrigerantPoint{
+		{-30, 272, 251}, {-20, 400, 243}, {-10, 573, 234}, {0, 799, 222}, {10, 1086, 210},
+		{20, 1444, 196}, {30, 1885, 180}, {40, 2420, 162}, {50, 3065, 139}, {60, 3837, 110},
+	}}
+	R32 = Refrigerant{Name: "R32", LiquidHeatCapacity: 1.9, VaporHeatCapacity: 1, table: []refrigerantPoint{
+		{-30, 273, 352}, {-20, 406, 342}, {-10, 582, 331}, {0, 813, 316}, {10, 1107, 300},
+		{20, 1475, 282}, {30, 1928, 261}, {40, 2478, 237}, {50, 3139, 209}, {60, 3924, 174},
+	}}
+	R454B = Refrigerant{Name: "R454B", Glide: 1.5, LiquidHeatCapacity: 1.65, VaporHeatCapacity: .95, table: []refrigerantPoint{
+		{-30, 237, 270}, {-20, 350, 262}, {-10, 505, 253}, {0, 707, 242}, {10, 966, 229},
+		{20, 1290, 214}, {30, 1690, 197}, {40, 2175, 177}, {50, 2760, 153}, {60, 3460, 123},
+	}}
+)
+
+// Returns the refrigerant by its name
+func RefrigerantByName(Name string) (Refrigerant, error) {
+	for _, r := range []Refrigerant{R410A, R32, R454B} {
+		if r.Name == Name {
+			return r, nil
+		}
+	}
+	return Refrigerant{}, fmt.Errorf("unknown refrigerant %v", Name)
+}
+
+// Returns the temperature range of the table
+func (s Refrigerant) Range() (float64, float64) {
+	if len(s.table) < 2 {
+		return math.NaN(), math.NaN()
+	}
+	return s.table[0].temperature, s.table[len(s.table)-1].temperature
+}
+
+// Returns the table row and the interpolation factor for the temperature
+func (s Refrigerant) interval(Temperature float64) (int, float64, error) {
+	min, max := s.Range()
+	if math.IsNaN(min) {
+		return 0, 0, errors.New("refrigerant table is empty")
+	}
+	if math.IsNaN(Temperature) || Temperature < min || Temperature > max {
+		return 0, 0, fmt.Errorf("%v at %v ºC: %w", s.Name, Temperature, ErrTemperatureOutOfRange)
+	}
+	i := 1
+	for i < len(s.table)-1 && s.table[i].temperature < Temperature {
+		i++
+	}
+	a, b := s.table[i-1], s.table[i]
+	return i, (Temperature - a.temperature) / (b.temperature - a.temperature), nil
+}
+
+// Returns the saturation pressure (kPa), interpolated in the logarithm of the pressure
+func (s Refrigerant) SaturationPressure(Temperature /* ºC */ float64) (float64 /* kPa */, error) {
+	i, k, err := s.interval(Temperature)
+	if err != nil {
+		return math.NaN(), err
+	}
+	a, b := s.table[i-1].pressure, s.table[i].pressure
+	return a * math.Pow(b/a, k), nil
+}
+
+// Returns the latent heat of evaporation (kJ/kg)
+func (s Refrigerant) LatentHeat(Temperature /* ºC */ float64) (float64 /* kJ/kg */, error) {
+	i, k, err := s.interval(Temperature)
+	if err != nil {
+		return math.NaN(), err
+	}
+	a, b := s.table[i-1].latentHeat, s.table[i].latentHeat
+	return a + k*(b-a), nil
+}
+
+// Returns the heat that one kg of refrigerant takes off in the evaporator (kJ/kg).
+// The liquid comes from the condenser subcooled by RefrigerantSubcooling
+func (s Refrigerant) RefrigeratingEffect(EvaporatingTemperature /* ºC */ float64, CondensingTemperature /* ºC */ float64, Superheat /* K */ float64) (float64 /* kJ/kg */, error) {
+	latent, err := s.LatentHeat(EvaporatingTemperature)
+	if err != nil {
+		return math.NaN(), err
+	}
+	liquid := math.Max(CondensingTemperature-RefrigerantSubcooling-EvaporatingTemperature, 0)
+	return latent - s.LiquidHeatCapacity*liquid + s.VaporHeatCapacity*Superheat, nil
+}
+
+// Returns the capacity and the consumed power (kW) of the condensing unit at the outdoor and evaporating temperatures.
+// The curve is interpolated linearly over the outdoor temperature and held constant outside of it
+func (s CondensingUnit) Performance(OutdoorTemperature /* ºC */ float64, EvaporatingTemperature /* ºC */ float64) (Capacity float64 /* kW */, Power float64 /* kW */, err error) {
+	if len(s.Curve) == 0 {
+		return 0, 0, errors.New("condensing unit curve is empty")
+	}
+	point := s.Curve[0]
+	switch last := s.Curve[len(s.Curve)-1]; {
+	case OutdoorTemperature <= point.OutdoorTemperature:
+	case OutdoorTemperature >= last.OutdoorTemperature:
+		point = last
+	default:
+		i := 1
+		for s.Curve[i].OutdoorTemperature < OutdoorTemperature {
+			i++
+		}
+		a, b := s.Curve[i-1], s.Curve[i]
+		k := (OutdoorTemperature - a.OutdoorTemperature) / (b.OutdoorTemperature - a.OutdoorTemperature)
+		point = CondensingUnitPoint{OutdoorTemperature, a.Capacity + k*(b.Capacity-a.Capacity), a.Power + k*(b.Power-a.Power)}
+	}
+	rating, capacity_factor, power_factor := s.RatingEvaporatingTemperature, s.CapacityFactor, s.PowerFactor
+	if rating == 0 {
+		rating = DefaultRatingEvaporatingTemperature
+	}
+	if capacity_factor == 0 {
+		capacity_factor = DefaultCondensingCapacityFactor
+	}
+	if power_factor == 0 {
+		power_factor = DefaultCondensingPowerFactor
+	}
+	Capacity = math.Max(point.Capacity*(1+capacity_factor*(EvaporatingTemperature-rating)), 0)
+	Power = math.Max(point.Power*(1+power_factor*(EvaporatingTemperature-rating)), 0)
+	return
+}
+
+func (s DXCoil) superheat() float64 {
+	if s.Superheat == 0 {
+		return DefaultEvaporatorSuperheat
+	}
+	return s.Superheat
+}
+
+// Returns the evaporator result at the selected evaporating temperature. The refrigerant
+// is taken at the mean temperature of the glide and does not change its temperature along the coil.
+// The water fields of the rating are left at zero, the refrigerant side is in DXResult
+func (s DXCoil) Rate(Inlet Air, VolumetricFlowrate /* m3/h */ float64, EvaporatingTemperature /* ºC */ float64) (out CoilRating, err error) {
+	if err = s.Coil.validate(); err != nil {
+		return
+	}
+	if err = Inlet.Validate(); err != nil {
+		return
+	}
+	if VolumetricFlowrate <= 0 {
+		return out, errors.New("invalid flowrate")
+	}
+	if _, err = s.Refrigerant.SaturationPressure(EvaporatingTemperature); err != nil {
+		return
+	}
+	refrigerant := EvaporatingTemperature + s.Refrigerant.Glide/2
+	out = CoilRating{
+		Inlet:              Inlet,
+		Outgoing:           Inlet,
+		VolumetricFlowrate: VolumetricFlowrate,
+	}
+	if refrigerant >= Inlet.Temperature {
+		return
+	}
+	surface := s.Coil.surface()
+	ho, efficiency, _, _ := s.Coil.airSide(Inlet, VolumetricFlowrate, surface)
+	out.PressureDrop = s.Coil.PressureDrop(Inlet, VolumetricFlowrate)
+
+	air_mass_flowrate := VolumetricFlowrate * Inlet.AirDensity() / SecondsInHour             // kg/s
+	air_heat_capacity := 1000 * (DryAirHeatCapacity + Inlet.Humidity*WaterSteamHeatCapacity) // J/kg K
+	outer := efficiency * ho * surface.outer                                                 // W/K
+	inner := EvaporatingHeatTransfer * surface.inner                                         // W/K
+
+	// Dry coil
+	ca := air_mass_flowrate * air_heat_capacity
+	power := (1 - math.Exp(-1/(1/outer+1/inner)/ca)) * ca * (Inlet.Temperature - refrigerant) // W
+	out.Outgoing.Temperature = Inlet.Temperature - power/ca
+
+	// Wet coil
+	p := Inlet.BarometricPressure()
+	if dew_point := Inlet.DewPoint(); refrigerant < dew_point {
+		cs := (saturationEnthalpy(dew_point, p) - saturationEnthalpy(refrigerant, p)) / (dew_point - refrigerant) * 1000 // J/kg K
+		ua := 1 / (air_heat_capacity/outer + cs/inner)                                                                   // kg/s
+		wet := (1 - math.Exp(-ua/air_mass_flowrate)) * air_mass_flowrate * (Inlet.Enthalpy() - saturationEnthalpy(refrigerant, p)) * 1000
+		if wet > power {
+			out.Wet = true
+			power = wet
+			out.Outgoing = wetCoilOutgoing(Inlet, air_mass_flowrate, outer/air_heat_capacity, wet)
+			out.CondensateMassFlowrate = air_mass_flowrate * (Inlet.Humidity - out.Outgoing.Humidity) * SecondsInHour
+			out.PressureDrop *= WetCoilPressureFactor
+		}
+	}
+	out.Power = -power / 1000
+	return
+}
+
+// Returns the result of the evaporator at the evaporating temperature and the condensing unit load
+func (s DXCoil) result(Inlet Air, VolumetricFlowrate float64, Unit CondensingUnit, OutdoorTemperature float64, EvaporatingTemperature float64) (out DXResult, err error) {
+	rating, err := s.Rate(Inlet, VolumetricFlowrate, EvaporatingTemperature)
+	if err != nil {
+		return
+	}
+	out = DXResult{
+		HeaterResult: HeaterResult{
+			Inlet:              Inlet,
+			Outgoing:           rating.Outgoing,
+			VolumetricFlowrate: VolumetricFlowrate,
+			PressureDrop:       rating.PressureDrop,
+			Power:              -rating.Power,
+		},
+		OutdoorTemperature:     OutdoorTemperature,
+		EvaporatingTemperature: EvaporatingTemperature,
+		CondensingTemperature:  OutdoorTemperature + CondensingTemperatureDifference,
+		CondensateMassFlowrate: rating.CondensateMassFlowrate,
+	}
+	if out.EvaporatingPressure, err = s.Refrigerant.SaturationPressure(EvaporatingTemperature); err != nil {
+		return
+	}
+	effect, err := s.Refrigerant.RefrigeratingEffect(EvaporatingTemperature, out.CondensingTemperature, s.superheat())
+	if err != nil {
+		return
+	}
+	out.RefrigerantMassFlowrate = out.Power / effect * SecondsInHour
+	capacity, power, err := Unit.Performance(OutdoorTemperature, EvaporatingTemperature)
+	if err != nil {
+		return
+	}
+	if capacity > 0 {
+		out.ElectricPower = power * math.Min(out.Power/capacity, 1)
+	}
+	if out.ElectricPower > 0 {
+		out.EER = out.Power / out.ElectricPower
+	}
+	return
+}
+
+// Returns the evaporating temperature at which the evaporator and the condensing unit have the same capacity
+func (s DXCoil) BalancePoint(Inlet Air, VolumetricFlowrate /* m3/h */ float64, Unit CondensingUnit, OutdoorTemperature /* ºC */ float64) (float64 /* ºC */, error) {
+	min, max := s.Refrigerant.Range()
+	min = math.Max(min, MinEvaporatingTemperature)
+	max = math.Min(max, Inlet.Temperature-s.Refrigerant.Glide/2)
+	var err error
+	t, root_err := FindRoot(func(t float64) float64 {
+		rating, e := s.Rate(Inlet, VolumetricFlowrate, t)
+		if e != nil {
+			err = e
+			return 0
+		}
+		capacity, _, e := Unit.Performance(OutdoorTemperature, t)
+		if e != nil {
+			err = e
+			return 0
+		}
+		return -rating.Power - capacity
+	}, min, max, 1e-4)
+	if err != nil {
+		return math.NaN(), err
+	}
+	if root_err != nil {
+		return math.NaN(), fmt.Errorf("no balance point of the evaporator and the condensing unit: %w", root_err)
+	}
+	return t, nil
+}
+
+// Returns the cooler result at the outdoor temperature. Capacity is the power at the balance point.
+// If it exceeds the task, the unit runs at part load with the evaporating temperature raised to
+// reach Task.Target exactly, otherwise LowCapacity is set
+func (s DXCoil) Select(Task HeaterTask, Unit CondensingUnit, OutdoorTemperature /* ºC */ float64) (out DXResult, err error) {
+	q := float64(Task.VolumetricFlowrate)
+	out = DXResult{
+		HeaterResult:       HeaterResult{Inlet: Task.Inlet, Outgoing: Task.Inlet, VolumetricFlowrate: q},
+		OutdoorTemperature: OutdoorTemperature,
+	}
+	if Task.VolumetricFlowrate == 0 || Task.Target >= Task.Inlet.Temperature {
+		return
+	}
+	balance, err := s.BalancePoint(Task.Inlet, q, Unit, OutdoorTemperature)
+	if err != nil {
+		return
+	}
+	design, err := s.result(Task.Inlet, q, Unit, OutdoorTemperature, balance)
+	if err != nil {
+		return
+	}
+	out = design
+	out.Capacity = design.Power
+	if design.Outgoing.Temperature > Task.Target {
+		out.LowCapacity = true
+		return
+	}
+	t, root_err := FindRoot(func(t float64) float64 {
+		rating, e := s.Rate(Task.Inlet, q, t)
+		if e != nil {
+			err = e
+		}
+		return Task.Target - rating.Outgoing.Temperature
+	}, balance, Task.Inlet.Temperature-s.Refrigerant.Glide/2, 1e-4)
+	if err != nil {
+		return
+	}
+	if root_err != nil {
+		return out, fmt.Errorf("target %v ºC: %w", Task.Target, root_err)
+	}
+	if out, err = s.result(Task.Inlet, q, Unit, OutdoorTemperature, t); err != nil {
+		return
+	}
+	out.Capacity = design.Power
+	return
+}
+
+// Returns the cooler result for both seasons at the outdoor temperatures
+func (s DXCoil) Select2(Task HeaterTask2, Unit CondensingUnit, SummerOutdoorTemperature /* ºC */ float64, WinterOutdoorTemperature /* ºC */ float64) (out DXResult2, err error) {
+	out = DXResult2{LongName: s.Coil.LongName, ShortName: s.Coil.ShortName, Length: s.Coil.Length}
+	if out.Summer, err = s.Select(Task.Summer, Unit, SummerOutdoorTemperature); err != nil {
+		return
+	}
+	out.Winter, err = s.Select(Task.Winter, Unit, WinterOutdoorTemperature)
+	return
+}
+
+func (s DXResult2) HeaterResult2() HeaterResult2 {
+	return HeaterResult2{LongName: s.LongName, ShortName: s.ShortName, Length: s.Length, Summer: s.Summer.HeaterResult, Winter: s.Winter.HeaterResult}
+}
+
+// Sets the direct expansion cooler of the unit
+func (s *UnitDescription) SetDirectExpansionCooler(Cooler DXResult2) {
+	s.IsDirectExpansion = true
+	s.IsChilledWater = false
+	s.Cooler = Cooler.HeaterResult2()
+	s.CoolerEER.Summer = Cooler.Summer.EER
+	s.CoolerEER.Winter = Cooler.Winter.EER
+}
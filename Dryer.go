@@ -0,0 +1,90 @@
+package HVAC
+
+import "errors"
+
+type (
+	DryerTask struct {
+		Process                        Air     // process air before the rotor
+		Regeneration                   Air     // regeneration air before the regeneration heater
+		RegenerationTemperature        float64 // ºC, after the regeneration heater
+		ProcessVolumetricFlowrate      float64 // m3/h
+		RegenerationVolumetricFlowrate float64 // m3/h
+	}
+	DryerResult struct {
+		Process               Air
+		ProcessOutgoing       Air
+		Regeneration          Air // after the regeneration heater
+		RegenerationOutgoing  Air
+		RegenerationHeaterPwr float64 // kW
+		MoistureRemoval       float64 // kg/h
+		HumidityEfficiency    float64
+	}
+	DryerResult2 struct {
+		LongName  string
+		ShortName string
+		Length    uint64
+		Summer    DryerResult
+		Winter    DryerResult
+	}
+)
+
+// Returns the desiccant wheel process. The process air is dried along the line of constant enthalpy
+// towards the relative humidity of the heated regeneration air; Efficiency is the part of this ideal
+// humidity difference the rotor achieves. The removal is limited by the saturation of the regeneration air
+func DesiccantWheel(Task DryerTask, Efficiency float64) (out DryerResult, err error) {
+	if Efficiency < 0 || Efficiency > 1 {
+		return out, errors.New("invalid dryer efficiency")
+	}
+	if Task.RegenerationTemperature < Task.Regeneration.Temperature {
+		return out, errors.New("regeneration temperature is below regeneration air temperature")
+	}
+	if Task.ProcessVolumetricFlowrate <= 0 || Task.RegenerationVolumetricFlowrate <= 0 {
+		return out, errors.New("invalid flowrate")
+	}
+	for _, a := range []Air{Task.Process, Task.Regeneration} {
+		if err = a.Validate(); err != nil {
+			return
+		}
+	}
+	out = DryerResult{
+		Process:            Task.Process,
+		ProcessOutgoing:    Task.Process,
+		Regeneration:       Task.Regeneration,
+		HumidityEfficiency: Efficiency,
+	}
+	out.Regeneration.Temperature = Task.RegenerationTemperature
+	out.RegenerationOutgoing = out.Regeneration
+	out.RegenerationHeaterPwr = AirHeatPowerAt(Task.Regeneration.Temperature, Task.Regeneration.Humidity, Task.RegenerationTemperature, Task.RegenerationVolumetricFlowrate, Task.Regeneration.BarometricPressure())
+
+	h := Task.Process.Enthalpy()
+	p := Task.Process.BarometricPressure()
+	humidity := func(t float64) float64 {
+		return (h - DryAirHeatCapacity*t) / (VaporizationEnthalpyAt0 + WaterSteamHeatCapacity*t)
+	}
+	regeneration_ratio := out.Regeneration.HumidityRatio()
+	if Task.Process.HumidityRatio() <= regeneration_ratio {
+		return
+	}
+	ideal := rootOrNearestBound(func(t float64) float64 {
+		return 100*humidity(t)/SaturationHumidityAt(t, p) - regeneration_ratio
+	}, Task.Process.Temperature, h/DryAirHeatCapacity, 1e-4)
+	out.ProcessOutgoing.Humidity = Task.Process.Humidity - Efficiency*(Task.Process.Humidity-humidity(ideal))
+	out.ProcessOutgoing.Temperature = (h - VaporizationEnthalpyAt0*out.ProcessOutgoing.Humidity) / (DryAirHeatCapacity + WaterSteamHeatCapacity*out.ProcessOutgoing.Humidity)
+
+	process_mass_flowrate := Task.ProcessVolumetricFlowrate * Task.Process.AirDensity()
+	regeneration_mass_flowrate := Task.RegenerationVolumetricFlowrate * out.Regeneration.AirDensity()
+	out.MoistureRemoval = process_mass_flowrate * (Task.Process.Humidity - out.ProcessOutgoing.Humidity)
+	// The regeneration air cannot carry away more water than it takes to saturate it
+	rh := out.Regeneration.Enthalpy()
+	saturated := rootOrNearestBound(func(t float64) float64 {
+		return AirEnthalpy(t, SaturationHumidityAt(t, p)) - rh
+	}, -100, Task.RegenerationTemperature, 1e-4)
+	if max_removal := regeneration_mass_flowrate * (SaturationHumidityAt(saturated, p) - out.Regeneration.Humidity); out.MoistureRemoval > max_removal {
+		out.MoistureRemoval = max_removal
+		out.ProcessOutgoing.Humidity = Task.Process.Humidity - max_removal/process_mass_flowrate
+		out.ProcessOutgoing.Temperature = (h - VaporizationEnthalpyAt0*out.ProcessOutgoing.Humidity) / (DryAirHeatCapacity + WaterSteamHeatCapacity*out.ProcessOutgoing.Humidity)
+	}
+	out.RegenerationOutgoing.Humidity += out.MoistureRemoval / regeneration_mass_flowrate
+	out.RegenerationOutgoing.Temperature = (rh - VaporizationEnthalpyAt0*out.RegenerationOutgoing.Humidity) / (DryAirHeatCapacity + WaterSteamHeatCapacity*out.RegenerationOutgoing.Humidity)
+	return
+}
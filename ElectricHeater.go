@@ -0,0 +1,235 @@
+package HVAC
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+)
+
+const MaxPhaseUnbalance = .1 // of the largest phase current
+
+type (
+	SupplyVoltage struct {
+		Phases  uint64  // 1 or 3
+		Voltage float64 // V, line-to-line for 3 phases
+	}
+	HeatingElement struct {
+		Name    string
+		Power   float64 // kW
+		Voltage float64 // V
+	}
+	ElectricHeater struct {
+		LongName               string
+		ShortName              string
+		Length                 uint64
+		FaceArea               float64 // m2
+		MinFaceVelocity        float64 // m/s
+		MaxOutgoingTemperature float64 // ºC
+		MaxElements            uint64  // zero means unlimited
+		MaxStages              uint64  // zero means one stage
+		Elements               []HeatingElement
+	}
+	ElectricHeaterResult struct {
+		HeaterResult
+		Element         HeatingElement
+		ElementQty      uint64
+		Stages          []float64  // kW
+		PhaseCurrents   [3]float64 // A, line currents
+		PhaseUnbalance  float64    // (max - min) / max of the line currents
+		Balanced        bool
+		FaceVelocity    float64 // m/s
+		MinFaceVelocity float64 // m/s
+		OverheatRisk    bool    // face velocity is too low or the air is heated above MaxOutgoingTemperature
+	}
+)
+
+var (
+	SinglePhase230 = SupplyVoltage{Phases: 1, Voltage: 230}
+	ThreePhase400  = SupplyVoltage{Phases: 3, Voltage: 400}
+)
+
+// Returns true if the element is connected phase-to-neutral, false if line-to-line.
+// The error is returned if the element does not fit the supply
+func (s SupplyVoltage) connection(Element HeatingElement) (bool, error) {
+	phase := s.Voltage
+	if s.Phases == 3 {
+		phase = s.Voltage / math.Sqrt(3)
+	}
+	switch {
+	case math.Abs(Element.Voltage-phase) <= .05*phase:
+		return true, nil
+	case s.Phases == 3 && math.Abs(Element.Voltage-s.Voltage) <= .05*s.Voltage:
+		return false, nil
+	}
+	return false, errors.New("element voltage does not fit the supply")
+}
+
+// Returns the line currents of the elements evenly distributed over the phases
+func (s SupplyVoltage) currents(Element HeatingElement, Qty uint64) (out [3]float64, err error) {
+	star, err := s.connection(Element)
+	if err != nil {
+		return
+	}
+	current := Element.Power * 1000 / Element.Voltage
+	if s.Phases != 3 {
+		out[0] = current * float64(Qty)
+		return
+	}
+	var branches [3]float64
+	for i := uint64(0); i < Qty; i++ {
+		branches[i%3] += current
+	}
+	if star {
+		return branches, nil
+	}
+	// Delta: branches are L1-L2, L2-L3, L3-L1
+	var phasors [3]complex128
+	for i := range branches {
+		phasors[i] = cmplx.Rect(branches[i], math.Pi/6-2*math.Pi/3*float64(i))
+	}
+	for i := range out {
+		out[i] = cmplx.Abs(phasors[i] - phasors[(i+2)%3])
+	}
+	return
+}
+
+func (s ElectricHeater) validate() error {
+	if s.FaceArea <= 0 || math.IsNaN(s.FaceArea) {
+		return errors.New("invalid electric heater face area")
+	}
+	return nil
+}
+
+// Returns the heater with the element type and quantity that cover the task with the least installed power.
+// For 3 phases the quantity is a multiple of 3. If no configuration covers the task, the most powerful
+// one is taken and LowCapacity is set
+func (s ElectricHeater) Select(Task HeaterTask, Supply SupplyVoltage) (out ElectricHeaterResult, err error) {
+	if err = s.validate(); err != nil {
+		return
+	}
+	if err = Task.Inlet.Validate(); err != nil {
+		return
+	}
+	required := 0.
+	if Task.Target > Task.Inlet.Temperature {
+		required = AirHeatPowerAt(Task.Inlet.Temperature, Task.Inlet.Humidity, Task.Target, float64(Task.VolumetricFlowrate), Task.Inlet.BarometricPressure())
+	}
+	step := uint64(1)
+	if Supply.Phases == 3 {
+		step = 3
+	}
+	found := false
+	for _, element := range s.Elements {
+		if _, e := Supply.connection(element); e != nil || element.Power <= 0 {
+			continue
+		}
+		qty := uint64(math.Ceil(required/element.Power/float64(step))) * step
+		if qty == 0 {
+			qty = step
+		}
+		if s.MaxElements != 0 && qty > s.MaxElements {
+			qty = s.MaxElements / step * step
+		}
+		if qty == 0 {
+			continue
+		}
+		power := float64(qty) * element.Power
+		best := float64(out.ElementQty) * out.Element.Power
+		switch {
+		case !found,
+			best < required && power > best,
+			power >= required && (power < best || (power == best && qty < out.ElementQty)):
+			out.Element, out.ElementQty, found = element, qty, true
+		}
+	}
+	if !found {
+		return out, errors.New("no heating element fits the supply")
+	}
+	out.HeaterResult = s.heaterResult(Task, float64(out.ElementQty)*out.Element.Power)
+	if out.PhaseCurrents, err = Supply.currents(out.Element, out.ElementQty); err != nil {
+		return
+	}
+	max, min := 0., math.Inf(1)
+	for i := uint64(0); i < Supply.Phases && i < 3; i++ {
+		max, min = math.Max(max, out.PhaseCurrents[i]), math.Min(min, out.PhaseCurrents[i])
+	}
+	if max > 0 {
+		out.PhaseUnbalance = (max - min) / max
+	}
+	out.Balanced = out.PhaseUnbalance <= MaxPhaseUnbalance
+	out.Stages = stagePowers(out.ElementQty/step, s.MaxStages, float64(step)*out.Element.Power)
+	out.FaceVelocity = float64(Task.VolumetricFlowrate) / SecondsInHour / s.FaceArea
+	out.MinFaceVelocity = s.MinFaceVelocity
+	full_power_temperature := AirHeatOutgoingTemperatureAt(out.Capacity, float64(Task.VolumetricFlowrate), Task.Inlet.Temperature, Task.Inlet.Humidity, Task.Inlet.BarometricPressure())
+	out.OverheatRisk = out.FaceVelocity < s.MinFaceVelocity || (s.MaxOutgoingTemperature != 0 && full_power_temperature > s.MaxOutgoingTemperature)
+	return
+}
+
+// Returns the power of the stages made of whole groups of elements, one element per phase.
+// The most stages up to MaxStages with the same number of groups are taken; if the groups
+// cannot be split equally, the stages differ by one group at most
+func stagePowers(Groups uint64, MaxStages uint64, GroupPower /* kW */ float64) []float64 {
+	stages := MaxStages
+	if stages == 0 {
+		stages = 1
+	}
+	if stages > Groups {
+		stages = Groups
+	}
+	equal := stages
+	for Groups%equal != 0 {
+		equal--
+	}
+	if equal > 1 {
+		stages = equal
+	}
+	out := make([]float64, stages)
+	for i := range out {
+		out[i] = float64(Groups/stages) * GroupPower
+		if uint64(i) < Groups%stages {
+			out[i] += GroupPower
+		}
+	}
+	return out
+}
+
+// Returns the heater result for the task with the selected installed power
+func (s ElectricHeater) heaterResult(Task HeaterTask, Capacity /* kW */ float64) HeaterResult {
+	out := HeaterResult{
+		Inlet:              Task.Inlet,
+		Outgoing:           Task.Inlet,
+		VolumetricFlowrate: float64(Task.VolumetricFlowrate),
+		Capacity:           Capacity,
+	}
+	if Task.Target <= Task.Inlet.Temperature || Task.VolumetricFlowrate == 0 {
+		return out
+	}
+	p := Task.Inlet.BarometricPressure()
+	out.Power = AirHeatPowerAt(Task.Inlet.Temperature, Task.Inlet.Humidity, Task.Target, out.VolumetricFlowrate, p)
+	out.Outgoing.Temperature = Task.Target
+	if out.Power > Capacity {
+		out.LowCapacity = true
+		out.Power = Capacity
+		out.Outgoing.Temperature = AirHeatOutgoingTemperatureAt(Capacity, out.VolumetricFlowrate, Task.Inlet.Temperature, Task.Inlet.Humidity, p)
+	}
+	return out
+}
+
+// Returns the heater result for both seasons. The elements are selected for the season
+// with the larger required power, the other season runs on the same heater
+func (s ElectricHeater) HeaterResult2(Task HeaterTask2, Supply SupplyVoltage) (out HeaterResult2, selected ElectricHeaterResult, err error) {
+	out = HeaterResult2{LongName: s.LongName, ShortName: s.ShortName, Length: s.Length}
+	power := func(t HeaterTask) float64 {
+		return float64(t.VolumetricFlowrate) * t.Inlet.AirDensity() * (t.Target - t.Inlet.Temperature)
+	}
+	design := Task.Winter
+	if power(Task.Summer) > power(Task.Winter) {
+		design = Task.Summer
+	}
+	if selected, err = s.Select(design, Supply); err != nil {
+		return
+	}
+	out.Summer = s.heaterResult(Task.Summer, selected.Capacity)
+	out.Winter = s.heaterResult(Task.Winter, selected.Capacity)
+	return
+}
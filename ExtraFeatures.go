@@ -1,6 +1,7 @@
 package HVAC
 
 import (
+	"errors"
 	"math"
 	"reflect"
 )
@@ -30,3 +31,94 @@ func LogisticSigmoid(x ...float64) []float64 {
 	}
 	return result
 }
+
+var ErrNoRoot = errors.New("function does not change its sign on the interval")
+
+// Finds the root of the function f on the [Low, High] interval by the Brent's method.
+// f must change its sign on the interval, otherwise ErrNoRoot is returned
+func FindRoot[T anyFloat](f func(T) T, Low T, High T, Tolerance T) (T, error) {
+	a, b := float64(Low), float64(High)
+	fa, fb := float64(f(Low)), float64(f(High))
+	if fa == 0 {
+		return Low, nil
+	}
+	if fb == 0 {
+		return High, nil
+	}
+	if math.Signbit(fa) == math.Signbit(fb) || math.IsNaN(fa) || math.IsNaN(fb) {
+		return T(math.NaN()), ErrNoRoot
+	}
+	if math.Abs(fa) < math.Abs(fb) {
+		a, b, fa, fb = b, a, fb, fa
+	}
+	c, fc, d := a, fa, a
+	bisected := true
+	for i := 0; i < 200 && fb != 0 && math.Abs(b-a) > float64(Tolerance); i++ {
+		var x float64
+		if fa != fc && fb != fc {
+			x = a*fb*fc/((fa-fb)*(fa-fc)) + b*fa*fc/((fb-fa)*(fb-fc)) + c*fa*fb/((fc-fa)*(fc-fb))
+		} else {
+			x = b - fb*(b-a)/(fb-fa)
+		}
+		if (x-(3*a+b)/4)*(x-b) >= 0 ||
+			(bisected && math.Abs(x-b) >= math.Abs(b-c)/2) ||
+			(!bisected && math.Abs(x-b) >= math.Abs(c-d)/2) ||
+			(bisected && math.Abs(b-c) < float64(Tolerance)) ||
+			(!bisected && math.Abs(c-d) < float64(Tolerance)) {
+			x = (a + b) / 2
+			bisected = true
+		} else {
+			bisected = false
+		}
+		fx := float64(f(T(x)))
+		d, c, fc = c, b, fb
+		if math.Signbit(fa) == math.Signbit(fx) {
+			a, fa = x, fx
+		} else {
+			b, fb = x, fx
+		}
+		if math.Abs(fa) < math.Abs(fb) {
+			a, b, fa, fb = b, a, fb, fa
+		}
+	}
+	return T(b), nil
+}
+
+// Finds the root of the monotonic function f on the [Low, High] interval by FindRoot.
+// If f does not change its sign on the interval, the bound with the smaller |f| is returned instead of ErrNoRoot,
+// so it is only for the searches clamped to a physical limit (e.g. the dew point of the completely dry air)
+func rootOrNearestBound(f func(float64) float64, Low float64, High float64, Tolerance float64) float64 {
+	root, err := FindRoot(f, Low, High, Tolerance)
+	if err == nil {
+		return root
+	}
+	if math.Abs(f(Low)) < math.Abs(f(High)) {
+		return Low
+	}
+	return High
+}
+
+// Finds the root of f on the [Low, High] interval closest to High. The interval is scanned in Points steps
+// first, so f may have several roots and jumps; a sign change across a jump is not taken as a root
+func highestRoot(f func(float64) float64, Low float64, High float64, Points int, Tolerance float64) (float64, error) {
+	step := (High - Low) / float64(Points)
+	b, fb := High, f(High)
+	if fb == 0 {
+		return b, nil
+	}
+	for i := Points - 1; i >= 0; i-- {
+		a := Low + step*float64(i)
+		fa := f(a)
+		if fa == 0 {
+			return a, nil
+		}
+		if math.Signbit(fa) != math.Signbit(fb) {
+			root, err := FindRoot(f, a, b, Tolerance)
+			if err == nil && math.Abs(f(root)) <= 1e-3*math.Max(math.Abs(fa), math.Abs(fb)) {
+				return root, nil
+			}
+		}
+		b, fb = a, fa
+	}
+	return math.NaN(), ErrNoRoot
+}
@@ -0,0 +1,57 @@
+package HVAC
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFindRoot(t *testing.T) {
+	tests := []struct {
+		name      string
+		f         func(float64) float64
+		low, high float64
+		want      float64
+		err       error
+	}{
+		{"square root", func(x float64) float64 { return x*x - 2 }, 0, 2, math.Sqrt2, nil},
+		{"reversed interval", func(x float64) float64 { return x*x - 2 }, 2, 0, math.Sqrt2, nil},
+		{"cosine", math.Cos, 0, 3, math.Pi / 2, nil},
+		{"steep exponent", func(x float64) float64 { return math.Exp(x) - 1e6 }, 0, 50, math.Log(1e6), nil},
+		{"root at the low bound", func(x float64) float64 { return x - 1 }, 1, 5, 1, nil},
+		{"root at the high bound", func(x float64) float64 { return x - 5 }, 1, 5, 5, nil},
+		{"no sign change", func(x float64) float64 { return x*x + 1 }, -1, 1, math.NaN(), ErrNoRoot},
+		{"even root", func(x float64) float64 { return x * x }, -1, 1, math.NaN(), ErrNoRoot},
+		{"not a number", func(x float64) float64 { return math.NaN() }, 0, 1, math.NaN(), ErrNoRoot},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := FindRoot(test.f, test.low, test.high, 1e-9)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("error %v, want %v", err, test.err)
+			}
+			if test.err != nil {
+				if !math.IsNaN(got) {
+					t.Errorf("root %v, want NaN", got)
+				}
+				return
+			}
+			if math.Abs(got-test.want) > 1e-8 {
+				t.Errorf("root %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFindRootFloat32(t *testing.T) {
+	got, err := FindRoot(func(x float32) float32 { return x*x*x - 27 }, 0, 10, 1e-5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(float64(got)-3) > 1e-4 {
+		t.Errorf("root %v, want 3", got)
+	}
+	if _, err := FindRoot(func(x float32) float32 { return x + 1 }, 0, 10, 1e-5); !errors.Is(err, ErrNoRoot) {
+		t.Errorf("error %v, want ErrNoRoot", err)
+	}
+}
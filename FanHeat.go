@@ -0,0 +1,71 @@
+package HVAC
+
+const DefaultMotorEfficiency = .9
+
+type (
+	// Placement of the supply blower. With the motor out of the airstream only the shaft power
+	// heats the air. A blow-through blower stands before the coils and heats their inlet air,
+	// a draw-through one stands after the coils and heats the supply air
+	FanHeat struct {
+		MotorInAirstream bool
+		BlowThrough      bool
+		MotorEfficiency  float64 // zero means DefaultMotorEfficiency
+	}
+)
+
+// Returns the part of the consumed power of the blower that heats the air (kW)
+func (s FanHeat) Power(Blower BlowerResp1) float64 {
+	if s.MotorInAirstream {
+		return Blower.ConsumingPower
+	}
+	efficiency := s.MotorEfficiency
+	if efficiency == 0 {
+		efficiency = DefaultMotorEfficiency
+	}
+	return Blower.ConsumingPower * efficiency
+}
+
+// Returns the air after the blower
+func (s FanHeat) Outgoing(Inlet Air, VolumetricFlowrate /* m3/h */ float64, Blower BlowerResp1) Air {
+	out := Inlet
+	if VolumetricFlowrate > 0 {
+		out.Temperature = AirHeatOutgoingTemperatureAt(s.Power(Blower), VolumetricFlowrate, Inlet.Temperature, Inlet.Humidity, Inlet.BarometricPressure())
+	}
+	return out
+}
+
+// Returns the coil task corrected by the blower heat. Blow-through raises the inlet temperature,
+// draw-through lowers the target by the temperature rise in the blower
+func (s FanHeat) CoilTask(Task HeaterTask, Blower BlowerResp1) HeaterTask {
+	q := float64(Task.VolumetricFlowrate)
+	if s.BlowThrough {
+		Task.Inlet = s.Outgoing(Task.Inlet, q, Blower)
+		return Task
+	}
+	target := Task.Inlet
+	target.Temperature = Task.Target
+	Task.Target -= s.Outgoing(target, q, Blower).Temperature - Task.Target
+	return Task
+}
+
+// Returns the coil tasks of both seasons corrected by the blower heat
+func (s FanHeat) CoilTask2(Task HeaterTask2, Blower BlowerResp) HeaterTask2 {
+	Task.Summer = s.CoilTask(Task.Summer, Blower.Summer)
+	Task.Winter = s.CoilTask(Task.Winter, Blower.Winter)
+	return Task
+}
+
+// Adds the blower heat to the supply air that leaves the last coil. With blow-through the heat
+// is already in the coil inlet (see CoilTask), so the supply is not changed
+func (s *UnitResult) AddFanHeat(Fan FanHeat, Blower BlowerResp1) {
+	if Fan.BlowThrough {
+		return
+	}
+	s.Supply = Fan.Outgoing(s.Supply, float64(s.SupplyFlowrate), Blower)
+}
+
+// Adds the supply blower heat to the supply air of both seasons
+func (s *UnitDescription) AddFanHeat(Fan FanHeat) {
+	s.Result.Summer.AddFanHeat(Fan, s.SupplyBlower.Summer)
+	s.Result.Winter.AddFanHeat(Fan, s.SupplyBlower.Winter)
+}
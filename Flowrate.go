@@ -0,0 +1,24 @@
+package HVAC
+
+// Standard air for the standard volumetric flowrate is at StandardTemperature and NormalPressure
+const StandardTemperature = 20 // ºC
+
+// Returns the mass flowrate of dry air (kg/h) for the actual volumetric flowrate at the air state
+func (s *Air) MassFlowrate(VolumetricFlowrate /* m3/h */ float64) float64 /* kg/h */ {
+	return VolumetricFlowrate * s.AirDensity()
+}
+
+// Returns the actual volumetric flowrate (m3/h) at the air state for the mass flowrate of dry air
+func (s *Air) VolumetricFlowrate(MassFlowrate /* kg/h */ float64) float64 /* m3/h */ {
+	return MassFlowrate / s.AirDensity()
+}
+
+// Returns the standard volumetric flowrate (m3/h at 20 ºC and NormalPressure) for the actual one at the air state
+func (s *Air) StandardFlowrate(VolumetricFlowrate /* m3/h */ float64) float64 /* m3/h */ {
+	return s.MassFlowrate(VolumetricFlowrate) / AirDensity(float64(StandardTemperature))
+}
+
+// Returns the actual volumetric flowrate at the air state for the standard one (m3/h at 20 ºC and NormalPressure)
+func (s *Air) ActualFlowrate(StandardFlowrate /* m3/h */ float64) float64 /* m3/h */ {
+	return s.VolumetricFlowrate(StandardFlowrate * AirDensity(float64(StandardTemperature)))
+}
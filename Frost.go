@@ -0,0 +1,163 @@
+package HVAC
+
+import (
+	"fmt"
+	"math"
+)
+
+const MinFrostOutdoorTemperature = -40 // ºC, the lowest outdoor temperature checked for frosting
+
+type (
+	// Result of the heat recovery protected from frosting. Fraction is the part of the supply flow bypassed
+	// around the device or the time share of defrosting. EfficiencyLoss is the drop of the temperature efficiency
+	FrostProtectionResult struct {
+		HeatRecoveryResult
+		PreHeated      Air // outdoor air after the preheater
+		Fraction       float64
+		EfficiencyLoss float64
+	}
+)
+
+// Returns the exhaust outlet temperature (ºC) below which the device frosts: the exhaust
+// condenses below its dew point and the condensate freezes below 0 ºC
+func FrostLimit(Inside Air) float64 {
+	return math.Min(0, Inside.DewPoint())
+}
+
+// Returns the device result with Freeze set if the exhaust leaves below the frost limit
+func CheckFrost(Device HeatRecoveryDevice, Task HeatRecoveryTask) (HeatRecoveryResult, error) {
+	out, err := Device.Calculate(Task)
+	out.Freeze = err == nil && out.Exhaust.Temperature < FrostLimit(Task.Inside)
+	return out, err
+}
+
+// Returns the outdoor air at the temperature with the relative humidity of the task
+func outdoorAt(Outside Air, Temperature float64) Air {
+	out := Outside
+	out.Temperature = Temperature
+	if Outside.Humidity > 0 {
+		out.SetHumidityRatio(Outside.HumidityRatio())
+	}
+	return out
+}
+
+// Returns the outdoor temperature (ºC) at which the device starts to frost.
+// The relative humidity of the outdoor air is kept from the task
+func FrostOnsetTemperature(Device HeatRecoveryDevice, Task HeatRecoveryTask) (float64 /* ºC */, error) {
+	limit := FrostLimit(Task.Inside)
+	var err error
+	t, root_err := FindRoot(func(t float64) float64 {
+		task := Task
+		task.Outside = outdoorAt(Task.Outside, t)
+		result, e := Device.Calculate(task)
+		if e != nil {
+			err = e
+			return 0
+		}
+		return result.Exhaust.Temperature - limit
+	}, MinFrostOutdoorTemperature, math.Min(limit, Task.Inside.Temperature), 1e-3)
+	if err != nil {
+		return math.NaN(), err
+	}
+	if root_err != nil {
+		return math.NaN(), fmt.Errorf("device does not frost above %v ºC: %w", MinFrostOutdoorTemperature, root_err)
+	}
+	return t, nil
+}
+
+// Returns the device result with the preheater that keeps the exhaust outlet at the frost limit.
+// The preheater power is in PreHeaterPwr. The efficiency is the temperature rise in the device
+// related to the original outdoor air, as the nominal one, so the preheater heat is not counted
+func FrostPreheater(Device HeatRecoveryDevice, Task HeatRecoveryTask) (out FrostProtectionResult, err error) {
+	if out.HeatRecoveryResult, err = CheckFrost(Device, Task); err != nil || !out.Freeze {
+		out.PreHeated = Task.Outside
+		return
+	}
+	nominal := out.TemperatureEfficiency
+	limit := FrostLimit(Task.Inside)
+	preheated := Task
+	t, root_err := FindRoot(func(t float64) float64 {
+		preheated.Outside.Temperature = t
+		result, e := Device.Calculate(preheated)
+		if e != nil {
+			err = e
+		}
+		return result.Exhaust.Temperature - limit
+	}, Task.Outside.Temperature, Task.Inside.Temperature, 1e-3)
+	if err != nil {
+		return
+	}
+	if root_err != nil {
+		return out, fmt.Errorf("preheater: %w", root_err)
+	}
+	preheated.Outside.Temperature = t
+	if out.HeatRecoveryResult, err = Device.Calculate(preheated); err != nil {
+		return
+	}
+	out.PreHeated = preheated.Outside
+	out.Outside = Task.Outside
+	out.PreHeaterPwr = AirHeatPowerAt(Task.Outside.Temperature, Task.Outside.Humidity, t, float64(Task.SupplyVolumetricFlowrate), Task.Outside.BarometricPressure())
+	out.TemperatureEfficiency = (out.Supply.Temperature - t) / (Task.Inside.Temperature - Task.Outside.Temperature)
+	out.EfficiencyLoss = nominal - out.TemperatureEfficiency
+	return
+}
+
+// Returns the preheater task for the selection of the preheater (see ElectricHeater.Select, WaterCoil.Select)
+func (s FrostProtectionResult) PreHeaterTask(VolumetricFlowrate /* m3/h */ uint64) HeaterTask {
+	return HeaterTask{Inlet: s.Outside, Target: s.PreHeated.Temperature, VolumetricFlowrate: VolumetricFlowrate}
+}
+
+// Returns the device result with the part of the supply bypassed around the device that keeps
+// the exhaust outlet at the frost limit. The supply is the mix of the bypassed and the recovered air
+func FrostBypass(Device HeatRecoveryDevice, Task HeatRecoveryTask) (out FrostProtectionResult, err error) {
+	out.PreHeated = Task.Outside
+	if out.HeatRecoveryResult, err = CheckFrost(Device, Task); err != nil || !out.Freeze {
+		return
+	}
+	nominal := out.TemperatureEfficiency
+	limit := FrostLimit(Task.Inside)
+	b, root_err := FindRoot(func(b float64) float64 {
+		result, e := bypassHeatRecovery(Device, Task, b)
+		if e != nil {
+			err = e
+		}
+		return result.Exhaust.Temperature - limit
+	}, 0, 1, 1e-4)
+	if err != nil {
+		return
+	}
+	if root_err != nil {
+		return out, fmt.Errorf("bypass: %w", root_err)
+	}
+	if out.HeatRecoveryResult, err = bypassHeatRecovery(Device, Task, b); err != nil {
+		return
+	}
+	out.Fraction = b
+	out.EfficiencyLoss = nominal - out.TemperatureEfficiency
+	return
+}
+
+// Returns the time averaged device result with defrost cycling. During defrosting the supply keeps running
+// with the outdoor air bypassing the device (or the wheel stopped) and the exhaust warms the device up;
+// the defrost time share keeps the average exhaust outlet at the frost limit
+func FrostDefrostCycling(Device HeatRecoveryDevice, Task HeatRecoveryTask) (out FrostProtectionResult, err error) {
+	out.PreHeated = Task.Outside
+	if out.HeatRecoveryResult, err = CheckFrost(Device, Task); err != nil || !out.Freeze {
+		return
+	}
+	limit := FrostLimit(Task.Inside)
+	running := (Task.Inside.Temperature - limit) / (Task.Inside.Temperature - out.Exhaust.Temperature)
+	nominal := out.TemperatureEfficiency
+	out.Fraction = 1 - running
+	out.Supply.Temperature = Task.Outside.Temperature + running*(out.Supply.Temperature-Task.Outside.Temperature)
+	out.Supply.Humidity = Task.Outside.Humidity + running*(out.Supply.Humidity-Task.Outside.Humidity)
+	out.Exhaust.Temperature = limit
+	out.Exhaust.Humidity = Task.Inside.Humidity + running*(out.Exhaust.Humidity-Task.Inside.Humidity)
+	out.CondensateMassFlowrate *= running
+	out.HeatRecovery *= running
+	out.TemperatureEfficiency *= running
+	out.HumidityEfficiency *= running
+	out.EfficiencyLoss = nominal - out.TemperatureEfficiency
+	out.Freeze = false
+	return
+}
@@ -32,6 +32,7 @@ const (
 	DryAirHeatCapacity        = 1.007            // kJ/kg K
 	WaterSteamHeatCapacity    = 2.0784           // kJ/kg K
 	SecondsInHour             = 3600             // s
+	VaporizationEnthalpyAt0   = 2501             // kJ/kg, used for the enthalpy of moist air
 )
 
 // First is kg/h, second l/h
@@ -44,6 +45,18 @@ func CoolingCondensationFlowrate[T anyFloat](InletHumidity T, OutgoingTemperatur
 	return water_mass_flowrate, water_volumetric_flowrate
 }
 
+// Same as CoolingCondensationFlowrate, but the inlet humidity is in kg/kg (as in Air.Humidity)
+// and the saturation state is taken at the selected barometric pressure.
+// First is kg/h, second l/h
+func CoolingCondensationFlowrateAt[T anyFloat](InletHumidity /* kg/kg */ T, OutgoingTemperature /* ºC */ T, VolumetricFlowrate /* m3/h */ T, Pressure /* Pa */ T) (T, T) {
+	water_mass_flowrate := (InletHumidity - .95*SaturationHumidityAt(OutgoingTemperature, Pressure)) * AirDensityAt(OutgoingTemperature, Pressure) * VolumetricFlowrate
+	if water_mass_flowrate < 0 {
+		water_mass_flowrate = 0
+	}
+	water_volumetric_flowrate := 1000 * water_mass_flowrate / (WaterDensity(OutgoingTemperature))
+	return water_mass_flowrate, water_volumetric_flowrate
+}
+
 // Returns the power of evaporation/condensation process
 func SteamToWaterPhaseTransitionPower[T anyFloat](MassFlowRate /* kg/h */ T) (TransitionPower T) {
 	TransitionPower = MassFlowRate * EnthalpyOfVaporization / SecondsInHour
@@ -52,24 +65,29 @@ func SteamToWaterPhaseTransitionPower[T anyFloat](MassFlowRate /* kg/h */ T) (Tr
 
 // Returns the density (kg/m3) of the air at the selected temperature and normal pressure
 func AirDensity[T anyFloat](Temperature T) (Density T) {
-	return (NormalPressure * AirMolarMass) / ((Temperature + CelsiusToKelvinDifference) * MolarGasConstant * 1000)
+	return AirDensityAt(Temperature, NormalPressure)
+}
+
+// Returns the density (kg/m3) of the air at the selected temperature and barometric pressure
+func AirDensityAt[T anyFloat](Temperature /* ºC */ T, Pressure /* Pa */ T) (Density T) {
+	return (Pressure * AirMolarMass) / ((Temperature + CelsiusToKelvinDifference) * MolarGasConstant * 1000)
 }
 
-// Returns the density of saturated steam at the selected temperature and normal pressure
+// Returns the barometric pressure at the selected altitude above sea level (standard atmosphere)
+func PressureAtAltitude[T anyFloat](Altitude /* m */ T) (Pressure T /* Pa */) {
+	return T(NormalPressure * math.Pow(1-2.25577e-5*float64(Altitude), 5.25588))
+}
+
+// Returns the density of saturated steam at the selected temperature.
+// It does not depend on the barometric pressure, so there is no pressure-aware variant
 func VaporDensity[T anyFloat](Temperature /* ºC */ T) (Density T) /* kg/m3 */ {
 	return (VaporPressure(Temperature) * WaterMolarMass) / ((Temperature + CelsiusToKelvinDifference) * MolarGasConstant)
 }
 
-// Returns the pressure of saturated steam at the selected temperature
+// Returns the pressure of saturated steam at the selected temperature.
+// The formulation is selected by SetSaturationFormula (Buck by default), see VaporPressureChecked for the range check
 func VaporPressure[T anyFloat](Temperature /* ºC */ T) (Pressure T /* kPa */) {
-	var p float64
-	t := float64(Temperature)
-	if t > 0 {
-		p = .61121 * math.Exp((18.678-t/234.5)*(t/(257.14+t)))
-	} else {
-		p = .61115 * math.Exp((23.036-t/333.7)*(t/(279.82+t)))
-	}
-	return T(p)
+	return T(saturationFormula.VaporPressure(float64(Temperature)))
 }
 
 // Returns the partial pressure of the water vapour at the selected temperature and humidity ratio
@@ -77,33 +95,58 @@ func PartialPressure[T anyFloat](Temperature /* ºC */ T, HumidityRatio /* % */
 	return HumidityRatio * .01 * VaporPressure(Temperature)
 }
 
+// Returns the humidity (kg/kg) of the saturated air at the selected temperature and normal pressure
+func SaturationHumidity[T anyFloat](Temperature /* ºC */ T) T /* kg/kg */ {
+	return SaturationHumidityAt(Temperature, NormalPressure)
+}
+
+// Returns the humidity (kg/kg) of the saturated air at the selected temperature and barometric pressure
+func SaturationHumidityAt[T anyFloat](Temperature /* ºC */ T, Pressure /* Pa */ T) T /* kg/kg */ {
+	return VaporDensity(Temperature) / AirDensityAt(Temperature, Pressure)
+}
+
+// Returns the specific enthalpy of moist air (kJ/kg of dry air), taken as zero for dry air at 0 ºC
+func AirEnthalpy[T anyFloat](Temperature /* ºC */ T, Humidity /* kg/kg */ T) T /* kJ/kg */ {
+	return DryAirHeatCapacity*Temperature + Humidity*(VaporizationEnthalpyAt0+WaterSteamHeatCapacity*Temperature)
+}
+
 // Returns the power required to heat/cool selected flowrate (Q) from the initial temperature (InletTemperature) to the target temperature (OutgoingTemperature)
-func AirHeatPower[T anyFloat](InletTemperature /* ºC */ T, Humidity /* g/kg */ T, OutgoingTemperature /* ºC */ T, VolumetricFlowrate /* m3/h */ T) T /* kW */ {
-	return T(math.Abs(float64(OutgoingTemperature-InletTemperature))) * VolumetricFlowrate * (AirDensity(InletTemperature) * (DryAirHeatCapacity + Humidity*WaterSteamHeatCapacity)) / SecondsInHour
+func AirHeatPower[T anyFloat](InletTemperature /* ºC */ T, Humidity /* kg/kg */ T, OutgoingTemperature /* ºC */ T, VolumetricFlowrate /* m3/h */ T) T /* kW */ {
+	return AirHeatPowerAt(InletTemperature, Humidity, OutgoingTemperature, VolumetricFlowrate, NormalPressure)
+}
+
+// Same as AirHeatPower, but at the selected barometric pressure
+func AirHeatPowerAt[T anyFloat](InletTemperature /* ºC */ T, Humidity /* kg/kg */ T, OutgoingTemperature /* ºC */ T, VolumetricFlowrate /* m3/h */ T, Pressure /* Pa */ T) T /* kW */ {
+	return T(math.Abs(float64(OutgoingTemperature-InletTemperature))) * VolumetricFlowrate * (AirDensityAt(InletTemperature, Pressure) * (DryAirHeatCapacity + Humidity*WaterSteamHeatCapacity)) / SecondsInHour
 }
 
 // Returns the temperature difference of the air before and after heating/cooling
 // HeatingPower - kW
 // VolumetricFlowrate - m3/h
 // InletTemperature - ºC
-// Humidity - g/kg
-func AirHeatOutgoingTemperature[T anyFloat](HeatingPower /* kW */ T, VolumetricFlowrate /* m3/h */ T, InletTemperature /* ºC */ T, Humidity /* g/kg */ T) (OutgoingTemperature /* ºC */ T) {
-	return HeatingPower/(VolumetricFlowrate*(AirDensity(InletTemperature)*(DryAirHeatCapacity+Humidity*WaterSteamHeatCapacity))/SecondsInHour) + InletTemperature
+// Humidity - kg/kg
+func AirHeatOutgoingTemperature[T anyFloat](HeatingPower /* kW */ T, VolumetricFlowrate /* m3/h */ T, InletTemperature /* ºC */ T, Humidity /* kg/kg */ T) (OutgoingTemperature /* ºC */ T) {
+	return AirHeatOutgoingTemperatureAt(HeatingPower, VolumetricFlowrate, InletTemperature, Humidity, NormalPressure)
+}
+
+// Same as AirHeatOutgoingTemperature, but at the selected barometric pressure
+func AirHeatOutgoingTemperatureAt[T anyFloat](HeatingPower /* kW */ T, VolumetricFlowrate /* m3/h */ T, InletTemperature /* ºC */ T, Humidity /* kg/kg */ T, Pressure /* Pa */ T) (OutgoingTemperature /* ºC */ T) {
+	return HeatingPower/(VolumetricFlowrate*(AirDensityAt(InletTemperature, Pressure)*(DryAirHeatCapacity+Humidity*WaterSteamHeatCapacity))/SecondsInHour) + InletTemperature
 }
 
 // Returns the power that the water flow (Q) gives off when cooled from InletTemperature to OutgoingTemperature
 // Q - water volumetric flowrate
 // InletTemperature - inlet water temperature
 // OutgoingTemperature - outgoing water temperature
-func WaterHeatPower(InletTemperature /* ºC */ float64, OutgoingTemperature /* ºC */ float64, VolumetricFlowrate /* dm3/h */ float64) float64 /* kW */ {
-	return float64(math.Abs(float64(OutgoingTemperature-InletTemperature))) * VolumetricFlowrate * WaterDensity(InletTemperature) * WaterHeatCapacity(InletTemperature) / (SecondsInHour * 1000)
+func WaterHeatPower[T anyFloat](InletTemperature /* ºC */ T, OutgoingTemperature /* ºC */ T, VolumetricFlowrate /* dm3/h */ T) T /* kW */ {
+	return T(math.Abs(float64(OutgoingTemperature-InletTemperature))) * VolumetricFlowrate * WaterDensity(InletTemperature) * WaterHeatCapacity(InletTemperature) / (SecondsInHour * 1000)
 }
 
 // Returns the temperature difference of the in- and outgoing water flow after it has heated/cooled the air
 // P - air heating power
 // Q - water volumetric flowrate
 // InletTemperature - inlet water temperature
-func WaterHeatTemperature(HeatingPower /* kW */ float64, VolumetricFlowrate /* dm3/h */ float64, InletTemperature /* ºC */ float64) float64 /* ºC */ {
+func WaterHeatTemperature[T anyFloat](HeatingPower /* kW */ T, VolumetricFlowrate /* dm3/h */ T, InletTemperature /* ºC */ T) T /* ºC */ {
 	return HeatingPower / (VolumetricFlowrate * WaterDensity(InletTemperature) * WaterHeatCapacity(InletTemperature) / (SecondsInHour * 1000))
 }
 
@@ -111,17 +154,6 @@ func WaterHeatTemperature(HeatingPower /* kW */ float64, VolumetricFlowrate /* d
 // P - required power
 // InletTemperature - inlet water temperature
 // OutgoingTemperature - outgoing water temperature
-func WaterHeatVolumetricFlowRate(HeatingPower /* kW */ float64, InletTemperature /* ºC */ float64, OutgoingTemperature /* ºC */ float64) float64 /* dm3/h */ {
-	return HeatingPower / (float64(math.Abs(float64(OutgoingTemperature-InletTemperature))) * WaterDensity(InletTemperature) * WaterHeatCapacity(InletTemperature) / (SecondsInHour * 1000))
-}
-
-func WaterDensity[T anyFloat](t T) T {
-	x := float64(t)
-	return T(1000 * (0.999922 + 0.0000475377*x - 7.34753*math.Pow10(-6)*math.Pow(x, 2) + 3.92894*math.Pow10(-8)*math.Pow(x, 3) - 1.2144*math.Pow10(-10)*math.Pow(x, 4)))
-}
-
-func WaterHeatCapacity[T anyFloat](t T) T {
-	x := float64(t)
-	y := 4.21701568841111 - 0.007849993116176771*x + 0.0010067730629546086*math.Pow(x, 2) - 0.00006957457567073951*math.Pow(x, 3) + 2.605607116793847*math.Pow10(-6)*math.Pow(x, 4) - 5.609144300760924*math.Pow10(-8)*math.Pow(x, 5) + 7.141795677520566*math.Pow10(-10)*math.Pow(x, 6) - 5.286838922592877*math.Pow10(-12)*math.Pow(x, 7) + 2.0881591874928128*math.Pow10(-14)*math.Pow(x, 8) - 3.366302230773775*math.Pow10(-17)*math.Pow(x, 9)
-	return T(y)
+func WaterHeatVolumetricFlowRate[T anyFloat](HeatingPower /* kW */ T, InletTemperature /* ºC */ T, OutgoingTemperature /* ºC */ T) T /* dm3/h */ {
+	return HeatingPower / (T(math.Abs(float64(OutgoingTemperature-InletTemperature))) * WaterDensity(InletTemperature) * WaterHeatCapacity(InletTemperature) / (SecondsInHour * 1000))
 }
@@ -0,0 +1,162 @@
+package HVAC
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	DefrostMinTemperature = -7  // ºC, below it the outdoor air is too dry to frost the coil
+	DefrostMaxTemperature = 7   // ºC, above it the outdoor coil does not frost
+	DefaultDefrostPenalty = .15 // capacity loss at 0 ºC
+)
+
+type (
+	// Heating capacity and COP of the heat pump at the outdoor temperature without the defrost penalty
+	HeatPumpPoint struct {
+		OutdoorTemperature float64 // ºC
+		Capacity           float64 // kW
+		COP                float64
+	}
+	// Reversible DX coil working as the condenser of the heat pump
+	HeatPump struct {
+		Coil                  DXCoil
+		MinOutdoorTemperature float64 // ºC, the heat pump stops below it. Zero means no lower limit
+		DefrostPenalty        float64 // capacity loss at 0 ºC. Zero means DefaultDefrostPenalty
+		Curve                 []HeatPumpPoint
+	}
+	// Heater selected for the rest of the heating load after the heat pump
+	BackupHeater func(Task HeaterTask) (HeaterResult, error)
+	// Result of the heat pump and the backup heater. HeatPump is the part covered by the heat pump,
+	// Backup is the part covered by the backup heater downstream
+	HeatPumpResult struct {
+		HeatPump           HeaterResult
+		Backup             HeaterResult
+		OutdoorTemperature float64 // ºC
+		DefrostFactor      float64 // part of the capacity left after defrosting
+		COP                float64 // with the defrost penalty
+		ElectricPower      float64 // kW, consumed by the heat pump
+	}
+)
+
+// Returns the capacity loss of defrosting. It grows linearly from both ends of the frosting range to the penalty at 0 ºC
+func (s HeatPump) DefrostFactor(OutdoorTemperature /* ºC */ float64) float64 {
+	penalty := s.DefrostPenalty
+	if penalty == 0 {
+		penalty = DefaultDefrostPenalty
+	}
+	switch {
+	case OutdoorTemperature <= DefrostMinTemperature || OutdoorTemperature >= DefrostMaxTemperature:
+		return 1
+	case OutdoorTemperature < 0:
+		return 1 - penalty*(OutdoorTemperature-DefrostMinTemperature)/-DefrostMinTemperature
+	}
+	return 1 - penalty*(DefrostMaxTemperature-OutdoorTemperature)/DefrostMaxTemperature
+}
+
+// Returns the heating capacity (kW) and COP at the outdoor temperature with the defrost penalty.
+// The curve is interpolated linearly and held constant outside of it, below MinOutdoorTemperature the capacity is zero
+func (s HeatPump) Performance(OutdoorTemperature /* ºC */ float64) (Capacity float64 /* kW */, COP float64, err error) {
+	if len(s.Curve) == 0 {
+		return 0, 0, errors.New("heat pump curve is empty")
+	}
+	if s.MinOutdoorTemperature != 0 && OutdoorTemperature < s.MinOutdoorTemperature {
+		return 0, 0, nil
+	}
+	point := s.Curve[0]
+	switch last := s.Curve[len(s.Curve)-1]; {
+	case OutdoorTemperature <= point.OutdoorTemperature:
+	case OutdoorTemperature >= last.OutdoorTemperature:
+		point = last
+	default:
+		i := 1
+		for s.Curve[i].OutdoorTemperature < OutdoorTemperature {
+			i++
+		}
+		a, b := s.Curve[i-1], s.Curve[i]
+		k := (OutdoorTemperature - a.OutdoorTemperature) / (b.OutdoorTemperature - a.OutdoorTemperature)
+		point = HeatPumpPoint{OutdoorTemperature, a.Capacity + k*(b.Capacity-a.Capacity), a.COP + k*(b.COP-a.COP)}
+	}
+	defrost := s.DefrostFactor(OutdoorTemperature)
+	// Defrosting takes off the heat and wastes the consumed power, so COP falls by the same part
+	return point.Capacity * defrost, point.COP * defrost, nil
+}
+
+// Returns the heat pump result for the task at the outdoor temperature. The part of the load
+// the heat pump does not cover is given to the backup heater. Without the backup LowCapacity is set
+func (s HeatPump) Select(Task HeaterTask, OutdoorTemperature /* ºC */ float64, Backup BackupHeater) (out HeatPumpResult, err error) {
+	if err = Task.Inlet.Validate(); err != nil {
+		return
+	}
+	q := float64(Task.VolumetricFlowrate)
+	p := Task.Inlet.BarometricPressure()
+	out = HeatPumpResult{
+		HeatPump:           HeaterResult{Inlet: Task.Inlet, Outgoing: Task.Inlet, VolumetricFlowrate: q},
+		OutdoorTemperature: OutdoorTemperature,
+		DefrostFactor:      s.DefrostFactor(OutdoorTemperature),
+	}
+	if s.Coil.Coil.validate() == nil && q > 0 {
+		out.HeatPump.PressureDrop = s.Coil.Coil.PressureDrop(Task.Inlet, q)
+	}
+	out.Backup = out.HeatPump
+	out.Backup.PressureDrop = 0
+	if q == 0 || Task.Target <= Task.Inlet.Temperature {
+		return
+	}
+	capacity, cop, err := s.Performance(OutdoorTemperature)
+	if err != nil {
+		return
+	}
+	out.COP = cop
+	out.HeatPump.Capacity = capacity
+	required := AirHeatPowerAt(Task.Inlet.Temperature, Task.Inlet.Humidity, Task.Target, q, p)
+	out.HeatPump.Power = math.Min(required, capacity)
+	out.HeatPump.Outgoing.Temperature = Task.Target
+	if required > capacity {
+		out.HeatPump.LowCapacity = true
+		out.HeatPump.Outgoing.Temperature = AirHeatOutgoingTemperatureAt(capacity, q, Task.Inlet.Temperature, Task.Inlet.Humidity, p)
+	}
+	if cop > 0 {
+		out.ElectricPower = out.HeatPump.Power / cop
+	}
+	out.Backup.Inlet = out.HeatPump.Outgoing
+	out.Backup.Outgoing = out.HeatPump.Outgoing
+	if !out.HeatPump.LowCapacity || Backup == nil {
+		return
+	}
+	out.Backup, err = Backup(HeaterTask{Inlet: out.HeatPump.Outgoing, Target: Task.Target, VolumetricFlowrate: Task.VolumetricFlowrate})
+	return
+}
+
+// Returns the heat pump result that heats the air from Inlet up to the supply target of the season
+func (s HeatPump) SelectForSeason(Season SeasonInitData, Inlet Air, Backup BackupHeater) (HeatPumpResult, error) {
+	return s.Select(HeaterTask{Inlet: Inlet, Target: Season.SupplyTarget.Temperature, VolumetricFlowrate: Season.SupplyVolumetricFlowrate}, Season.Outdoor.Temperature, Backup)
+}
+
+// Returns the result of the heat pump and the backup heater together
+func (s HeatPumpResult) HeaterResult() HeaterResult {
+	out := s.HeatPump
+	out.Outgoing = s.Backup.Outgoing
+	out.Capacity += s.Backup.Capacity
+	out.Power += s.Backup.Power
+	out.PressureDrop += s.Backup.PressureDrop
+	out.WaterVolumetricFlowrate = s.Backup.WaterVolumetricFlowrate
+	out.WaterPressureDrop = s.Backup.WaterPressureDrop
+	out.LowCapacity = s.HeatPump.LowCapacity && (s.Backup.Capacity == 0 || s.Backup.LowCapacity)
+	return out
+}
+
+// Returns the backup heater of the electric heater at the supply voltage
+func (s ElectricHeater) Backup(Supply SupplyVoltage) BackupHeater {
+	return func(Task HeaterTask) (HeaterResult, error) {
+		out, err := s.Select(Task, Supply)
+		return out.HeaterResult, err
+	}
+}
+
+// Returns the backup heater of the water coil at the design water temperatures
+func (s WaterCoil) Backup(Fluid Fluid, WaterInletTemperature /* ºC */ float64, WaterOutgoingTemperature /* ºC */ float64) BackupHeater {
+	return func(Task HeaterTask) (HeaterResult, error) {
+		return s.Select(Task, Fluid, WaterInletTemperature, WaterOutgoingTemperature)
+	}
+}
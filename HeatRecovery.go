@@ -0,0 +1,79 @@
+package HVAC
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+type (
+	// Heat recovery device of the unit: plate heat exchanger, thermal wheel, run-around loop
+	HeatRecoveryDevice interface {
+		Calculate(Task HeatRecoveryTask) (HeatRecoveryResult, error)
+		Calculate2(Task HeatRecoveryTask2) (HeatRecoveryResult2, error)
+	}
+	// Heat recovery devices by name
+	HeatRecoveryCatalog map[string]HeatRecoveryDevice
+)
+
+// Returns the results of the named devices for the task
+func (s HeatRecoveryCatalog) Response(Request RequestType5) (ResponseType5, error) {
+	out := make(ResponseType5, len(Request.Names))
+	for _, name := range Request.Names {
+		device, ok := s[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown heat recovery device %v", name)
+		}
+		result, err := device.Calculate2(Request.Task)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", name, err)
+		}
+		out[name] = result
+	}
+	return out, nil
+}
+
+func (s HeatRecoveryTask) validate() error {
+	if err := s.Inside.Validate(); err != nil {
+		return err
+	}
+	if err := s.Outside.Validate(); err != nil {
+		return err
+	}
+	if s.SupplyVolumetricFlowrate == 0 || s.ExhaustVolumetricFlowrate == 0 {
+		return errors.New("invalid flowrate")
+	}
+	return nil
+}
+
+// Returns the result without heat recovery: the supply is the outside air, the exhaust is the inside air
+func (s HeatRecoveryTask) passThrough() HeatRecoveryResult {
+	return HeatRecoveryResult{Supply: s.Outside, Exhaust: s.Inside, Inside: s.Inside, Outside: s.Outside}
+}
+
+// Returns the supply temperature efficiency by EN 308
+func temperatureEfficiency(Task HeatRecoveryTask, Supply Air) float64 {
+	if Task.Inside.Temperature == Task.Outside.Temperature {
+		return 0
+	}
+	return (Supply.Temperature - Task.Outside.Temperature) / (Task.Inside.Temperature - Task.Outside.Temperature)
+}
+
+// Returns the device result with the part of the supply flow bypassed around the device.
+// The supply is the mix of the bypassed and the recovered air
+func bypassHeatRecovery(Device HeatRecoveryDevice, Task HeatRecoveryTask, Fraction float64) (out HeatRecoveryResult, err error) {
+	q := float64(Task.SupplyVolumetricFlowrate)
+	task := Task
+	task.SupplyVolumetricFlowrate = uint64(math.Round((1 - Fraction) * q))
+	if task.SupplyVolumetricFlowrate == 0 {
+		return Task.passThrough(), nil
+	}
+	if out, err = Device.Calculate(task); err != nil {
+		return
+	}
+	through := float64(task.SupplyVolumetricFlowrate)
+	out.Supply, _ = MixAir(out.Supply, through, Task.Outside, q-through)
+	out.TemperatureEfficiency = temperatureEfficiency(Task, out.Supply)
+	out.HeatRecovery = math.Abs(AirHeatPowerAt(Task.Outside.Temperature, Task.Outside.Humidity, out.Supply.Temperature, q, Task.Outside.BarometricPressure()))
+	return
+}
@@ -0,0 +1,107 @@
+package HVAC
+
+import (
+	"fmt"
+	"math"
+)
+
+const MinRotorSpeed = .2 // rpm, the lowest speed of the speed control
+
+type (
+	// Result of the heat recovery modulated to the supply target. BypassFraction is the part of the supply flow
+	// passing the bypass damper; RotorSpeed is the speed the wheel runs at
+	HeatRecoveryControlResult struct {
+		HeatRecoveryResult
+		BypassFraction float64
+		RotorSpeed     float64 // rpm
+	}
+)
+
+// Returns the modulation (0 is full heat recovery, 1 is none) that gives the supply target temperature.
+// If the target is out of the modulation range, the end closer to the target is taken
+func modulate(Task HeatRecoveryTask, Supply func(float64) (float64, error)) (float64, error) {
+	target := Task.SupplyTarget.Temperature
+	full, err := Supply(0)
+	if err != nil {
+		return math.NaN(), err
+	}
+	none, err := Supply(1)
+	if err != nil {
+		return math.NaN(), err
+	}
+	if (full-target)*(none-target) > 0 {
+		if math.Abs(full-target) <= math.Abs(none-target) {
+			return 0, nil
+		}
+		return 1, nil
+	}
+	m, root_err := FindRoot(func(m float64) float64 {
+		t, e := Supply(m)
+		if e != nil {
+			err = e
+		}
+		return t - target
+	}, 0, 1, 1e-4)
+	if err != nil {
+		return math.NaN(), err
+	}
+	if root_err != nil {
+		return math.NaN(), fmt.Errorf("supply target %v ºC: %w", target, root_err)
+	}
+	return m, nil
+}
+
+// Returns the device result with the bypass damper set to reach Task.SupplyTarget. The device and the open bypass
+// are in parallel, so the supply side pressure drop is the larger of them; the other one is throttled.
+// BypassPressureDrop is the pressure drop of the open bypass at the full supply flowrate
+func BypassControl(Device HeatRecoveryDevice, Task HeatRecoveryTask, BypassPressureDrop /* Pa */ float64) (out HeatRecoveryControlResult, err error) {
+	if err = Task.validate(); err != nil {
+		return
+	}
+	b, err := modulate(Task, func(b float64) (float64, error) {
+		result, err := bypassHeatRecovery(Device, Task, b)
+		return result.Supply.Temperature, err
+	})
+	if err != nil {
+		return
+	}
+	if out.HeatRecoveryResult, err = bypassHeatRecovery(Device, Task, b); err != nil {
+		return
+	}
+	out.BypassFraction = b
+	if b == 1 {
+		// The device still has the exhaust flowing through it
+		full, err := Device.Calculate(Task)
+		if err != nil {
+			return out, err
+		}
+		out.ExhaustPressureDrop = full.ExhaustPressureDrop
+	}
+	out.SupplyPressureDrop = math.Max(out.SupplyPressureDrop, BypassPressureDrop*b*b)
+	return
+}
+
+// Returns the wheel result with the rotor speed set to reach Task.SupplyTarget. The speed is
+// modulated from RotorSpeed down to MinRotorSpeed; if the target needs less recovery, the wheel
+// keeps turning at MinRotorSpeed and the supply is warmer (or cooler) than the target
+func (s ThermalWheel) SpeedControl(Task HeatRecoveryTask) (out HeatRecoveryControlResult, err error) {
+	full := s.speed()
+	at := func(m float64) ThermalWheel {
+		wheel := s
+		wheel.RotorSpeed = full - m*(full-MinRotorSpeed)
+		return wheel
+	}
+	m, err := modulate(Task, func(m float64) (float64, error) {
+		result, err := at(m).Calculate(Task)
+		return result.Supply.Temperature, err
+	})
+	if err != nil {
+		return
+	}
+	wheel := at(m)
+	if out.HeatRecoveryResult, err = wheel.Calculate(Task); err != nil {
+		return
+	}
+	out.RotorSpeed = wheel.RotorSpeed
+	return
+}
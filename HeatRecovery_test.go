@@ -0,0 +1,95 @@
+package HVAC
+
+import (
+	"math"
+	"testing"
+)
+
+// Seasons of the heat recovery tests: -30 ºC outdoor against 22 ºC/40 % indoor in winter,
+// 32 ºC/40 % outdoor against 24 ºC/50 % indoor in summer, 3000 m3/h on both sides
+func testHeatRecoveryTask() HeatRecoveryTask2 {
+	winter_inside, winter_outside := Air{Temperature: 22}, Air{Temperature: -30}
+	winter_inside.SetHumidityRatio(40)
+	winter_outside.SetHumidityRatio(90)
+	summer_inside, summer_outside := Air{Temperature: 24}, Air{Temperature: 32}
+	summer_inside.SetHumidityRatio(50)
+	summer_outside.SetHumidityRatio(40)
+	return HeatRecoveryTask2{
+		Summer: HeatRecoveryTask{Inside: summer_inside, Outside: summer_outside, SupplyVolumetricFlowrate: 3000, ExhaustVolumetricFlowrate: 3000},
+		Winter: HeatRecoveryTask{Inside: winter_inside, Outside: winter_outside, SupplyVolumetricFlowrate: 3000, ExhaustVolumetricFlowrate: 3000},
+	}
+}
+
+// Returns the enthalpy flows (kW) gained by the supply air and lost by the exhaust air
+func heatRecoveryBalance(Task HeatRecoveryTask, Result HeatRecoveryResult) (supply float64, exhaust float64) {
+	supply = float64(Task.SupplyVolumetricFlowrate) * Task.Outside.AirDensity() * (Result.Supply.Enthalpy() - Task.Outside.Enthalpy()) / SecondsInHour
+	exhaust = float64(Task.ExhaustVolumetricFlowrate) * Task.Inside.AirDensity() * (Task.Inside.Enthalpy() - Result.Exhaust.Enthalpy()) / SecondsInHour
+	return
+}
+
+// Checks the properties every heat recovery device keeps in both seasons:
+// the outlets lie between the outdoor and indoor temperatures, the efficiencies are below 1
+// and match the temperatures, and the supply gains what the exhaust loses within Balance (relative)
+func checkHeatRecovery(t *testing.T, Device HeatRecoveryDevice, Balance float64) HeatRecoveryResult2 {
+	t.Helper()
+	task := testHeatRecoveryTask()
+	result, err := Device.Calculate2(task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, season := range []struct {
+		name   string
+		task   HeatRecoveryTask
+		result HeatRecoveryResult
+	}{{"summer", task.Summer, result.Summer}, {"winter", task.Winter, result.Winter}} {
+		out, in, r := season.task.Outside.Temperature, season.task.Inside.Temperature, season.result
+		for _, outlet := range []struct {
+			name        string
+			temperature float64
+		}{{"supply", r.Supply.Temperature}, {"exhaust", r.Exhaust.Temperature}} {
+			if (outlet.temperature-out)*(outlet.temperature-in) >= 0 {
+				t.Errorf("%v: %v %v ºC is not between %v and %v ºC", season.name, outlet.name, outlet.temperature, out, in)
+			}
+		}
+		if r.TemperatureEfficiency <= 0 || r.TemperatureEfficiency >= 1 {
+			t.Errorf("%v: temperature efficiency %v is out of (0, 1)", season.name, r.TemperatureEfficiency)
+		}
+		if want := (r.Supply.Temperature - out) / (in - out); math.Abs(r.TemperatureEfficiency-want) > 1e-3 {
+			t.Errorf("%v: temperature efficiency %v, want %v from the supply temperature", season.name, r.TemperatureEfficiency, want)
+		}
+		if r.HumidityEfficiency < 0 || r.HumidityEfficiency >= 1 {
+			t.Errorf("%v: humidity efficiency %v is out of [0, 1)", season.name, r.HumidityEfficiency)
+		}
+		supply, exhaust := heatRecoveryBalance(season.task, r)
+		if math.Abs(supply-exhaust) > Balance*math.Abs(exhaust) {
+			t.Errorf("%v: supply gains %v kW, exhaust loses %v kW", season.name, supply, exhaust)
+		}
+	}
+	return result
+}
+
+var testPlate = PlateHeatExchanger{CounterFlow: true, PlateLength: .8, PlateWidth: .8, StackHeight: .8, PlateSpacing: 3, PlateThickness: .2}
+
+func TestPlateHeatExchanger(t *testing.T) {
+	task := testHeatRecoveryTask()
+	result := checkHeatRecovery(t, testPlate, 1e-3)
+	for _, season := range []struct {
+		name   string
+		task   HeatRecoveryTask
+		result HeatRecoveryResult
+	}{{"summer", task.Summer, result.Summer}, {"winter", task.Winter, result.Winter}} {
+		// The plates pass no moisture, so the supply humidity stays and the recovery is all the supply gains
+		if season.result.Supply.Humidity != season.task.Outside.Humidity || season.result.HumidityEfficiency != 0 {
+			t.Errorf("%v: supply humidity %v kg/kg (efficiency %v), want %v kg/kg", season.name,
+				season.result.Supply.Humidity, season.result.HumidityEfficiency, season.task.Outside.Humidity)
+		}
+		supply, _ := heatRecoveryBalance(season.task, season.result)
+		if math.Abs(season.result.HeatRecovery-math.Abs(supply)) > 1e-2*math.Abs(supply) {
+			t.Errorf("%v: heat recovery %v kW, the supply gains %v kW", season.name, season.result.HeatRecovery, supply)
+		}
+	}
+	if result.Summer.CondensateMassFlowrate != 0 || result.Winter.CondensateMassFlowrate <= 0 {
+		t.Errorf("condensate %v kg/h in summer and %v kg/h in winter, want only in winter",
+			result.Summer.CondensateMassFlowrate, result.Winter.CondensateMassFlowrate)
+	}
+}
@@ -0,0 +1,83 @@
+package HVAC
+
+import "errors"
+
+const SteamTemperature = 100 // ºC, steam supplied by steam humidifiers
+
+type (
+	HumidifierResult struct {
+		Inlet                Air
+		Outgoing             Air
+		VolumetricFlowrate   float64 // m3/h
+		WaterMassFlowrate    float64 // kg/h, evaporated water or steam
+		Power                float64 // kW, steam generation
+		SaturationEfficiency float64 // media humidifiers only
+		LowCapacity          bool
+	}
+)
+
+// Returns the isothermal (steam) humidification process up to TargetHumidity.
+// The steam enthalpy slightly raises the air temperature
+func SteamHumidifierProcess(Inlet Air, TargetHumidity /* kg/kg */ float64, VolumetricFlowrate /* m3/h */ float64) (out HumidifierResult, err error) {
+	if err = Inlet.Validate(); err != nil {
+		return
+	}
+	out = HumidifierResult{Inlet: Inlet, Outgoing: Inlet, VolumetricFlowrate: VolumetricFlowrate}
+	if TargetHumidity <= Inlet.Humidity {
+		return
+	}
+	h := Inlet.Enthalpy() + (TargetHumidity-Inlet.Humidity)*(VaporizationEnthalpyAt0+WaterSteamHeatCapacity*SteamTemperature)
+	out.Outgoing.Humidity = TargetHumidity
+	out.Outgoing.Temperature = (h - VaporizationEnthalpyAt0*TargetHumidity) / (DryAirHeatCapacity + WaterSteamHeatCapacity*TargetHumidity)
+	if TargetHumidity > out.Outgoing.SaturationHumidity() {
+		return HumidifierResult{}, errors.New("target humidity is above saturation")
+	}
+	out.WaterMassFlowrate = VolumetricFlowrate * Inlet.AirDensity() * (TargetHumidity - Inlet.Humidity)
+	out.Power = SteamToWaterPhaseTransitionPower(out.WaterMassFlowrate)
+	return
+}
+
+// Returns the adiabatic (media) humidification process up to TargetHumidity.
+// The air is humidified along the line of constant wet-bulb temperature. If the target
+// requires a saturation efficiency above MaxSaturationEfficiency, the process is limited by it and LowCapacity is set
+func MediaHumidifierProcess(Inlet Air, TargetHumidity /* kg/kg */ float64, VolumetricFlowrate /* m3/h */ float64, MaxSaturationEfficiency float64) (out HumidifierResult, err error) {
+	if MaxSaturationEfficiency <= 0 || MaxSaturationEfficiency > 1 {
+		return out, errors.New("invalid saturation efficiency")
+	}
+	if err = Inlet.Validate(); err != nil {
+		return
+	}
+	out = HumidifierResult{Inlet: Inlet, Outgoing: Inlet, VolumetricFlowrate: VolumetricFlowrate}
+	if TargetHumidity <= Inlet.Humidity {
+		return
+	}
+	wet_bulb := Inlet.WetBulb()
+	hw := WaterHeatCapacity(wet_bulb) * wet_bulb // enthalpy of the evaporated water
+	h := Inlet.Enthalpy() - Inlet.Humidity*hw
+	out.Outgoing.Humidity = TargetHumidity
+	out.Outgoing.Temperature = (h + TargetHumidity*hw - VaporizationEnthalpyAt0*TargetHumidity) / (DryAirHeatCapacity + WaterSteamHeatCapacity*TargetHumidity)
+	if Inlet.Temperature > wet_bulb {
+		out.SaturationEfficiency = (Inlet.Temperature - out.Outgoing.Temperature) / (Inlet.Temperature - wet_bulb)
+	}
+	if out.SaturationEfficiency > MaxSaturationEfficiency || TargetHumidity > out.Outgoing.SaturationHumidity() {
+		out.LowCapacity = true
+		out.SaturationEfficiency = MaxSaturationEfficiency
+		out.Outgoing.Temperature = Inlet.Temperature - MaxSaturationEfficiency*(Inlet.Temperature-wet_bulb)
+		out.Outgoing.Humidity = (h - DryAirHeatCapacity*out.Outgoing.Temperature) / (VaporizationEnthalpyAt0 + WaterSteamHeatCapacity*out.Outgoing.Temperature - hw)
+	}
+	out.WaterMassFlowrate = VolumetricFlowrate * Inlet.AirDensity() * (out.Outgoing.Humidity - Inlet.Humidity)
+	return
+}
+
+// Returns the humidifier result. WaterVolumetricFlowrate is the water or steam consumption in l/h
+func (s HumidifierResult) HeaterResult() HeaterResult {
+	return HeaterResult{
+		Inlet:                   s.Inlet,
+		Outgoing:                s.Outgoing,
+		VolumetricFlowrate:      s.VolumetricFlowrate,
+		Capacity:                s.Power,
+		Power:                   s.Power,
+		WaterVolumetricFlowrate: 1000 * s.WaterMassFlowrate / WaterDensity(s.Outgoing.Temperature),
+		LowCapacity:             s.LowCapacity,
+	}
+}
@@ -0,0 +1,149 @@
+package HVAC
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	PlateThermalConductivity = 200 // W/m K, aluminium
+	PlateLaminarNusselt      = 7.54
+	PlateEntryLossFactor     = 1.5 // dynamic pressures lost at the channel inlet and outlet
+)
+
+type (
+	// Plate heat exchanger with the supply and exhaust channels alternating in the stack.
+	// The supply flows along PlateLength. In the cross-flow exchanger the exhaust flows along
+	// PlateWidth, in the counter-flow one against the supply along PlateLength
+	PlateHeatExchanger struct {
+		LongName       string
+		ShortName      string
+		Length         uint64
+		CounterFlow    bool
+		PlateLength    float64 // m
+		PlateWidth     float64 // m
+		StackHeight    float64 // m
+		PlateSpacing   float64 // mm, the channel gap
+		PlateThickness float64 // mm
+	}
+	// Flow in the channels of one side
+	plateChannels struct {
+		h            float64 // W/m2 K
+		pressureDrop float64 // Pa
+	}
+)
+
+func (s PlateHeatExchanger) validate() error {
+	if s.PlateLength <= 0 || s.PlateWidth <= 0 || s.PlateSpacing <= 0 || s.PlateThickness < 0 || s.channels() < 1 {
+		return errors.New("invalid plate heat exchanger geometry")
+	}
+	return nil
+}
+
+// Returns the number of channels of each side
+func (s PlateHeatExchanger) channels() float64 {
+	return math.Floor(s.StackHeight * 1000 / (s.PlateSpacing + s.PlateThickness) / 2)
+}
+
+// Returns the heat transfer area of the plates (m2)
+func (s PlateHeatExchanger) Area() float64 {
+	return s.PlateLength * s.PlateWidth * (2*s.channels() - 1)
+}
+
+// Returns the heat transfer coefficient and pressure drop of the side with the flow along FlowLength.
+// The flow between parallel plates is laminar with the Hausen correction for the entry length
+func (s PlateHeatExchanger) side(State Air, VolumetricFlowrate /* m3/h */ float64, FlowLength float64, ChannelWidth float64) (out plateChannels) {
+	gap := s.PlateSpacing / 1000
+	dh := 2 * gap
+	density := State.AirDensity()
+	velocity := VolumetricFlowrate / SecondsInHour / (s.channels() * ChannelWidth * gap)
+	viscosity := airViscosity(State.Temperature)
+	re := density * velocity * dh / viscosity
+	conductivity := 1000 * (DryAirHeatCapacity + State.Humidity*WaterSteamHeatCapacity) * viscosity / AirPrandtl
+	gz := dh / FlowLength * re * AirPrandtl
+	out.h = (PlateLaminarNusselt + .03*gz/(1+.016*math.Pow(gz, 2./3))) * conductivity / dh
+	friction := 96 / re
+	if re >= 2300 {
+		friction = .3164 * math.Pow(re, -.25)
+	}
+	out.pressureDrop = (friction*FlowLength/dh + PlateEntryLossFactor) * density * velocity * velocity / 2
+	return
+}
+
+// Returns the effectiveness of the exchanger, both flows unmixed in the cross-flow one
+func (s PlateHeatExchanger) effectiveness(NTU float64, CapacityRatio float64) float64 {
+	if s.CounterFlow {
+		return coilEffectiveness(NTU, CapacityRatio, 4)
+	}
+	return coilEffectiveness(NTU, CapacityRatio, 1)
+}
+
+// Returns the supply and exhaust outlet states. If the exhaust cools below its dew point,
+// the exhaust side is rated by the enthalpy method and the larger of the dry and wet duties is taken
+func (s PlateHeatExchanger) Calculate(Task HeatRecoveryTask) (out HeatRecoveryResult, err error) {
+	if err = s.validate(); err != nil {
+		return
+	}
+	if err = Task.validate(); err != nil {
+		return
+	}
+	out = Task.passThrough()
+	qs, qe := float64(Task.SupplyVolumetricFlowrate), float64(Task.ExhaustVolumetricFlowrate)
+	exhaust_width := s.PlateLength
+	exhaust_length := s.PlateWidth
+	if s.CounterFlow {
+		exhaust_width, exhaust_length = s.PlateWidth, s.PlateLength
+	}
+	supply := s.side(Task.Outside, qs, s.PlateLength, s.PlateWidth)
+	exhaust := s.side(Task.Inside, qe, exhaust_length, exhaust_width)
+	out.SupplyPressureDrop = supply.pressureDrop
+	out.ExhaustPressureDrop = exhaust.pressureDrop
+	if Task.Inside.Temperature == Task.Outside.Temperature {
+		return
+	}
+
+	area := s.Area()
+	wall := s.PlateThickness / 1000 / PlateThermalConductivity
+	supply_mass_flowrate := qs * Task.Outside.AirDensity() / SecondsInHour // kg/s
+	exhaust_mass_flowrate := qe * Task.Inside.AirDensity() / SecondsInHour
+	supply_heat_capacity := 1000 * (DryAirHeatCapacity + Task.Outside.Humidity*WaterSteamHeatCapacity) // J/kg K
+	exhaust_heat_capacity := 1000 * (DryAirHeatCapacity + Task.Inside.Humidity*WaterSteamHeatCapacity)
+
+	// Dry exchanger
+	cs := supply_mass_flowrate * supply_heat_capacity
+	ce := exhaust_mass_flowrate * exhaust_heat_capacity
+	cmin, cmax := math.Min(cs, ce), math.Max(cs, ce)
+	ua := area / (1/supply.h + wall + 1/exhaust.h)                                                             // W/K
+	power := s.effectiveness(ua/cmin, cmin/cmax) * cmin * (Task.Inside.Temperature - Task.Outside.Temperature) // W, to the supply
+	out.Exhaust.Temperature = Task.Inside.Temperature - power/ce
+
+	// Wet exhaust side
+	p := Task.Inside.BarometricPressure()
+	if dew_point := Task.Inside.DewPoint(); power > 0 && Task.Outside.Temperature < dew_point {
+		slope := (saturationEnthalpy(dew_point, p) - saturationEnthalpy(Task.Outside.Temperature, p)) / (dew_point - Task.Outside.Temperature) * 1000 // J/kg K
+		ca := exhaust_mass_flowrate
+		cw := cs / slope
+		cmin, cmax := math.Min(ca, cw), math.Max(ca, cw)
+		ua := 1 / (exhaust_heat_capacity/(area*exhaust.h) + slope*(1/supply.h+wall)/area) // kg/s
+		wet := s.effectiveness(ua/cmin, cmin/cmax) * cmin * (Task.Inside.Enthalpy() - saturationEnthalpy(Task.Outside.Temperature, p)) * 1000
+		if wet > power {
+			power = wet
+			out.Exhaust = wetCoilOutgoing(Task.Inside, exhaust_mass_flowrate, area*exhaust.h/exhaust_heat_capacity, wet)
+			out.CondensateMassFlowrate = exhaust_mass_flowrate * (Task.Inside.Humidity - out.Exhaust.Humidity) * SecondsInHour
+		}
+	}
+	out.Supply.Temperature = Task.Outside.Temperature + power/cs
+	out.HeatRecovery = math.Abs(power) / 1000
+	out.TemperatureEfficiency = temperatureEfficiency(Task, out.Supply)
+	return
+}
+
+// Returns the exchanger result for both seasons
+func (s PlateHeatExchanger) Calculate2(Task HeatRecoveryTask2) (out HeatRecoveryResult2, err error) {
+	out = HeatRecoveryResult2{LongName: s.LongName, ShortName: s.ShortName, Length: s.Length}
+	if out.Summer, err = s.Calculate(Task.Summer); err != nil {
+		return
+	}
+	out.Winter, err = s.Calculate(Task.Winter)
+	return
+}
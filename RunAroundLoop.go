@@ -0,0 +1,217 @@
+package HVAC
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const DefaultPumpEfficiency = .5
+
+type (
+	// Run-around heat recovery: the supply and exhaust coils are connected by a pumped loop,
+	// so the air flows stay fully separated
+	RunAroundLoop struct {
+		LongName                string
+		ShortName               string
+		Length                  uint64
+		SupplyCoil              WaterCoil
+		ExhaustCoil             WaterCoil
+		Fluid                   Fluid   // nil means water
+		PipePressureDrop        float64 // kPa, of the piping and the fittings at WaterVolumetricFlowrate
+		PumpEfficiency          float64 // zero means DefaultPumpEfficiency
+		WaterVolumetricFlowrate float64 // dm3/h. Zero means the flowrate of the maximum net heat recovery
+	}
+	RunAroundResult struct {
+		HeatRecoveryResult
+		WaterVolumetricFlowrate      float64 // dm3/h
+		SupplyCoilWaterTemperature   float64 // ºC, at the inlet of the supply coil
+		ExhaustCoilWaterTemperature  float64 // ºC, at the inlet of the exhaust coil
+		SupplyCoilWaterPressureDrop  float64 // kPa
+		ExhaustCoilWaterPressureDrop float64 // kPa
+		PumpPressure                 float64 // kPa
+		PumpHydraulicPower           float64 // kW
+		PumpPower                    float64 // kW, consumed
+	}
+	RunAroundResult2 struct {
+		LongName  string
+		ShortName string
+		Length    uint64
+		Summer    RunAroundResult
+		Winter    RunAroundResult
+	}
+)
+
+// Sets the loop fluid by the heater antifreeze
+func (s *RunAroundLoop) SetFluid(Extra Extra) error {
+	fluid, err := Extra.HeaterFluid()
+	if err != nil {
+		return err
+	}
+	s.Fluid = fluid
+	return nil
+}
+
+func (s RunAroundLoop) fluid() Fluid {
+	if s.Fluid == nil {
+		return Water{}
+	}
+	return s.Fluid
+}
+
+// Returns the loop result at the selected water flowrate. The loop temperatures are balanced so
+// that the water leaves the exhaust coil at the temperature it enters the supply coil
+func (s RunAroundLoop) rate(Task HeatRecoveryTask, WaterVolumetricFlowrate float64) (out RunAroundResult, err error) {
+	fluid := s.fluid()
+	out = RunAroundResult{HeatRecoveryResult: Task.passThrough(), WaterVolumetricFlowrate: WaterVolumetricFlowrate}
+	qs, qe := float64(Task.SupplyVolumetricFlowrate), float64(Task.ExhaustVolumetricFlowrate)
+	var supply, exhaust CoilRating
+	cycle := func(t float64) float64 {
+		var e error
+		if supply, e = s.SupplyCoil.Rate(Task.Outside, qs, fluid, t, WaterVolumetricFlowrate); e != nil {
+			err = e
+			return 0
+		}
+		if exhaust, e = s.ExhaustCoil.Rate(Task.Inside, qe, fluid, supply.WaterOutgoingTemperature, WaterVolumetricFlowrate); e != nil {
+			err = e
+			return 0
+		}
+		return exhaust.WaterOutgoingTemperature - t
+	}
+	t, root_err := FindRoot(cycle, math.Min(Task.Outside.Temperature, Task.Inside.Temperature), math.Max(Task.Outside.Temperature, Task.Inside.Temperature), 1e-4)
+	if err != nil {
+		return
+	}
+	if root_err != nil {
+		return out, fmt.Errorf("loop balance at %v dm3/h: %w", WaterVolumetricFlowrate, root_err)
+	}
+	cycle(t)
+	if err != nil {
+		return
+	}
+	out.Supply = supply.Outgoing
+	out.Exhaust = exhaust.Outgoing
+	out.SupplyPressureDrop = supply.PressureDrop
+	out.ExhaustPressureDrop = exhaust.PressureDrop
+	out.CondensateMassFlowrate = exhaust.CondensateMassFlowrate
+	out.HeatRecovery = math.Abs(supply.Power)
+	out.TemperatureEfficiency = temperatureEfficiency(Task, out.Supply)
+	out.SupplyCoilWaterTemperature = t
+	out.ExhaustCoilWaterTemperature = supply.WaterOutgoingTemperature
+	out.SupplyCoilWaterPressureDrop = supply.WaterPressureDrop
+	out.ExhaustCoilWaterPressureDrop = exhaust.WaterPressureDrop
+	out.PumpPressure = supply.WaterPressureDrop + exhaust.WaterPressureDrop + s.PipePressureDrop
+	out.PumpHydraulicPower = out.PumpPressure * WaterVolumetricFlowrate / 1000 / SecondsInHour
+	efficiency := s.PumpEfficiency
+	if efficiency == 0 {
+		efficiency = DefaultPumpEfficiency
+	}
+	out.PumpPower = out.PumpHydraulicPower / efficiency
+	return
+}
+
+// Returns the water flowrate (dm3/h) of the maximum net heat recovery, the heat recovered less the pump power.
+// The range from the fifth of the flowrate with the heat capacity of the smaller air flow up to twice the flowrate
+// of the turbulent flow in both coils is scanned, because the laminar to turbulent transition in the tubes makes
+// the recovery jump. The turbulent flowrate is a candidate too, the best point is refined by the golden section
+func (s RunAroundLoop) OptimalWaterFlowrate(Task HeatRecoveryTask) (float64 /* dm3/h */, error) {
+	if err := Task.validate(); err != nil {
+		return math.NaN(), err
+	}
+	fluid := s.fluid()
+	t := (Task.Outside.Temperature + Task.Inside.Temperature) / 2
+	air := math.Min(float64(Task.SupplyVolumetricFlowrate)*Task.Outside.AirDensity(), float64(Task.ExhaustVolumetricFlowrate)*Task.Inside.AirDensity()) * DryAirHeatCapacity
+	matched := air / (fluid.Density(t) * fluid.HeatCapacity(t)) * 1000 // dm3/h
+	var err error
+	recovery := func(q float64) float64 {
+		var result RunAroundResult
+		if result, err = s.rate(Task, math.Exp(q)); err != nil {
+			return math.Inf(-1)
+		}
+		return result.HeatRecovery - result.PumpPower
+	}
+	// The coldest water is the most viscous one
+	cold := math.Min(Task.Outside.Temperature, Task.Inside.Temperature)
+	turbulent := math.Max(s.SupplyCoil.turbulentWaterFlowrate(fluid, cold), s.ExhaustCoil.turbulentWaterFlowrate(fluid, cold))
+	const points = 32
+	low, high := math.Log(matched/5), math.Log(math.Max(matched*5, turbulent*2))
+	step := (high - low) / points
+	candidates := []float64{math.Log(turbulent)}
+	for i := 0; i <= points; i++ {
+		candidates = append(candidates, low+step*float64(i))
+	}
+	best, best_value := low, math.Inf(-1)
+	for _, q := range candidates {
+		if value := recovery(q); value > best_value {
+			best, best_value = q, value
+		}
+		if err != nil {
+			return math.NaN(), err
+		}
+	}
+	ratio := (math.Sqrt(5) - 1) / 2
+	a, b := best-step, best+step
+	c, d := b-ratio*(b-a), a+ratio*(b-a)
+	fc, fd := recovery(c), recovery(d)
+	for b-a > 1e-3 && err == nil {
+		if fc > fd {
+			b, d, fd = d, c, fc
+			c = b - ratio*(b-a)
+			fc = recovery(c)
+		} else {
+			a, c, fc = c, d, fd
+			d = a + ratio*(b-a)
+			fd = recovery(d)
+		}
+	}
+	if err != nil {
+		return math.NaN(), err
+	}
+	return math.Exp((a + b) / 2), nil
+}
+
+// Returns the loop result. The fluid is checked against freezing at the outdoor temperature
+func (s RunAroundLoop) Rate(Task HeatRecoveryTask) (out RunAroundResult, err error) {
+	if err = Task.validate(); err != nil {
+		return
+	}
+	if err = CheckFreezeProtection(s.fluid(), math.Min(Task.Outside.Temperature, Task.Inside.Temperature)); err != nil {
+		return
+	}
+	if s.WaterVolumetricFlowrate < 0 {
+		return out, errors.New("invalid water flowrate")
+	}
+	flowrate := s.WaterVolumetricFlowrate
+	if flowrate == 0 {
+		if flowrate, err = s.OptimalWaterFlowrate(Task); err != nil {
+			return out, fmt.Errorf("optimal water flowrate: %w", err)
+		}
+	}
+	return s.rate(Task, flowrate)
+}
+
+// Returns the loop result without the water side figures
+func (s RunAroundLoop) Calculate(Task HeatRecoveryTask) (HeatRecoveryResult, error) {
+	out, err := s.Rate(Task)
+	return out.HeatRecoveryResult, err
+}
+
+// Returns the loop result for both seasons
+func (s RunAroundLoop) Calculate2(Task HeatRecoveryTask2) (HeatRecoveryResult2, error) {
+	out, err := s.Rate2(Task)
+	return out.HeatRecoveryResult2(), err
+}
+
+// Returns the loop result with the water side figures for both seasons
+func (s RunAroundLoop) Rate2(Task HeatRecoveryTask2) (out RunAroundResult2, err error) {
+	out = RunAroundResult2{LongName: s.LongName, ShortName: s.ShortName, Length: s.Length}
+	if out.Summer, err = s.Rate(Task.Summer); err != nil {
+		return
+	}
+	out.Winter, err = s.Rate(Task.Winter)
+	return
+}
+
+func (s RunAroundResult2) HeatRecoveryResult2() HeatRecoveryResult2 {
+	return HeatRecoveryResult2{LongName: s.LongName, ShortName: s.ShortName, Length: s.Length, Summer: s.Summer.HeatRecoveryResult, Winter: s.Winter.HeatRecoveryResult}
+}
@@ -0,0 +1,58 @@
+package HVAC
+
+import (
+	"math"
+	"testing"
+)
+
+func testRunAroundLoop(t *testing.T) RunAroundLoop {
+	fluid, err := NewFluid(EthyleneGlycol, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coil := testCoil
+	coil.Rows = 6
+	return RunAroundLoop{SupplyCoil: coil, ExhaustCoil: coil, Fluid: fluid}
+}
+
+func TestRunAroundLoop(t *testing.T) {
+	task := testHeatRecoveryTask()
+	result := checkHeatRecovery(t, testRunAroundLoop(t), 1e-2)
+	for _, season := range []struct {
+		name   string
+		task   HeatRecoveryTask
+		result HeatRecoveryResult
+	}{{"summer", task.Summer, result.Summer}, {"winter", task.Winter, result.Winter}} {
+		// The streams are separated, so the supply humidity stays and the recovery is all the supply gains
+		if season.result.Supply.Humidity != season.task.Outside.Humidity || season.result.HumidityEfficiency != 0 {
+			t.Errorf("%v: supply humidity %v kg/kg (efficiency %v), want %v kg/kg", season.name,
+				season.result.Supply.Humidity, season.result.HumidityEfficiency, season.task.Outside.Humidity)
+		}
+		supply, _ := heatRecoveryBalance(season.task, season.result)
+		if math.Abs(season.result.HeatRecovery-math.Abs(supply)) > 1e-2*math.Abs(supply) {
+			t.Errorf("%v: heat recovery %v kW, the supply gains %v kW", season.name, season.result.HeatRecovery, supply)
+		}
+	}
+}
+
+func TestRunAroundOptimalWaterFlowrate(t *testing.T) {
+	loop := testRunAroundLoop(t)
+	task := testHeatRecoveryTask().Winter
+	optimum, err := loop.Rate(task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	best := optimum.HeatRecovery - optimum.PumpPower
+	// No flowrate of the scan, from the laminar flow in the tubes to twice the optimum, recovers more net of the pump
+	for _, fraction := range []float64{.1, .25, .5, .8, .9, 1.1, 1.25, 1.5, 2} {
+		loop.WaterVolumetricFlowrate = fraction * optimum.WaterVolumetricFlowrate
+		result, err := loop.Rate(task)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if net := result.HeatRecovery - result.PumpPower; net > best+1e-3 {
+			t.Errorf("%v dm3/h recovers %v kW net, more than %v kW at the optimal %v dm3/h",
+				loop.WaterVolumetricFlowrate, net, best, optimum.WaterVolumetricFlowrate)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package HVAC
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Formulation of the saturated water vapour pressure
+type SaturationFormula uint8
+
+const (
+	Buck         SaturationFormula = iota // Buck (1996), over water above 0 ºC and over ice below
+	Magnus                                // Magnus-Tetens with Alduchov-Eskridge coefficients
+	HylandWexler                          // Hyland-Wexler (1983), used by ASHRAE
+	IAPWS                                 // IAPWS-95 saturation line (Wagner-Pruss) and IAPWS-2011 sublimation line
+)
+
+var (
+	ErrUnknownSaturationFormula = errors.New("unknown saturation pressure formula")
+	ErrTemperatureOutOfRange    = fmt.Errorf("temperature: %w", ErrOutOfRange)
+)
+
+// Formulation used by VaporPressure and everything built on it. Buck by default
+var saturationFormula = Buck
+
+// Selects the formulation used by VaporPressure and everything built on it.
+// It is meant to be set once on start up, not concurrently with the calculations
+func SetSaturationFormula(Formula SaturationFormula) error {
+	if Formula > IAPWS {
+		return ErrUnknownSaturationFormula
+	}
+	saturationFormula = Formula
+	return nil
+}
+
+// Returns the formulation used by VaporPressure
+func GetSaturationFormula() SaturationFormula {
+	return saturationFormula
+}
+
+func (s SaturationFormula) String() string {
+	switch s {
+	case Buck:
+		return "Buck"
+	case Magnus:
+		return "Magnus"
+	case HylandWexler:
+		return "Hyland-Wexler"
+	case IAPWS:
+		return "IAPWS"
+	}
+	return fmt.Sprintf("SaturationFormula(%d)", uint8(s))
+}
+
+// Returns the temperature range (ºC) the formulation is valid for
+func (s SaturationFormula) Range() (Min float64, Max float64) {
+	switch s {
+	case Buck:
+		return -80, 100
+	case Magnus:
+		return -45, 60
+	case HylandWexler:
+		return -100, 200
+	case IAPWS:
+		return -100, 373.946
+	}
+	return math.NaN(), math.NaN()
+}
+
+// Returns the pressure of saturated steam (kPa) at the selected temperature without the range check
+func (s SaturationFormula) VaporPressure(Temperature /* ºC */ float64) (Pressure float64 /* kPa */) {
+	t := Temperature
+	k := Temperature + CelsiusToKelvinDifference
+	switch s {
+	case Magnus:
+		if t > 0 {
+			return .61094 * math.Exp(17.625*t/(243.04+t))
+		}
+		return .61121 * math.Exp(22.587*t/(273.86+t))
+	case HylandWexler:
+		if t > 0 {
+			return math.Exp(-5.8002206e3/k+1.3914993-4.8640239e-2*k+4.1764768e-5*k*k-1.4452093e-8*k*k*k+6.5459673*math.Log(k)) / 1000
+		}
+		return math.Exp(-5.6745359e3/k+6.3925247-9.677843e-3*k+6.2215701e-7*k*k+2.0747825e-9*k*k*k-9.484024e-13*k*k*k*k+4.1635019*math.Log(k)) / 1000
+	case IAPWS:
+		if k >= 273.16 {
+			tau := 1 - k/647.096
+			return 22064 * math.Exp(647.096/k*(-7.85951783*tau+1.84408259*math.Pow(tau, 1.5)-11.7866497*math.Pow(tau, 3)+
+				22.6807411*math.Pow(tau, 3.5)-15.9618719*math.Pow(tau, 4)+1.80122502*math.Pow(tau, 7.5)))
+		}
+		theta := k / 273.16
+		return .611657 * math.Exp((-21.2144006*math.Pow(theta, .00333333333)+27.3203819*math.Pow(theta, 1.20666667)-6.10598130*math.Pow(theta, 1.70333333))/theta)
+	}
+	if t > 0 {
+		return .61121 * math.Exp((18.678-t/234.5)*(t/(257.14+t)))
+	}
+	return .61115 * math.Exp((23.036-t/333.7)*(t/(279.82+t)))
+}
+
+// Returns the pressure of saturated steam at the selected temperature by the selected formulation.
+// The error is ErrTemperatureOutOfRange if the temperature is out of the formulation range, the pressure is returned anyway
+func VaporPressureBy[T anyFloat](Formula SaturationFormula, Temperature /* ºC */ T) (Pressure T /* kPa */, err error) {
+	if Formula > IAPWS {
+		return T(math.NaN()), ErrUnknownSaturationFormula
+	}
+	Pressure = T(Formula.VaporPressure(float64(Temperature)))
+	if min, max := Formula.Range(); float64(Temperature) < min || float64(Temperature) > max || math.IsNaN(float64(Temperature)) {
+		err = fmt.Errorf("%w: %v ºC is out of %v..%v ºC for %v formula", ErrTemperatureOutOfRange, Temperature, min, max, Formula)
+	}
+	return
+}
+
+// Same as VaporPressure, but reports the temperature out of the range of the selected formulation
+func VaporPressureChecked[T anyFloat](Temperature /* ºC */ T) (Pressure T /* kPa */, err error) {
+	return VaporPressureBy(saturationFormula, Temperature)
+}
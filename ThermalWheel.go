@@ -0,0 +1,217 @@
+package HVAC
+
+import (
+	"errors"
+	"math"
+)
+
+// Rotor types of the thermal wheel
+const (
+	CondensationRotor = 0 // bare aluminium, moisture is transferred only by condensation
+	EnthalpyRotor     = 1 // hygroscopic coating
+	SorptionRotor     = 2 // desiccant coating
+)
+
+const (
+	DefaultRotorSpeed        = 10   // rpm
+	WheelMatrixDensity       = 2700 // kg/m3
+	WheelMatrixHeatCapacity  = .9   // kJ/kg K
+	WheelLaminarNusselt      = 3
+	WheelLaminarFriction     = 53 // Darcy friction factor by Reynolds number, sinusoidal channels
+	WheelEntryLossFactor     = 1.5
+	WheelPurgeFlushingFactor = 1 // purge flow to the carried over volume when the purge sector flushes the channels
+)
+
+// Moisture transfer of the rotor types. The coating stores moisture like a matrix StorageRatio times
+// larger than the heat capacity one, SorptionFactor is the share of the ideal transfer the coating reaches.
+// Evaporation is the share of the condensate evaporated back into the supply at the large NTU,
+// the rest is drained or blown off with the exhaust
+const (
+	WheelEnthalpySorptionFactor  = .7
+	WheelEnthalpyStorageRatio    = 2
+	WheelSorptionSorptionFactor  = .95
+	WheelSorptionStorageRatio    = 5
+	WheelCondensationEvaporation = .5
+	WheelEnthalpyEvaporation     = .8
+	WheelSorptionEvaporation     = .95
+)
+
+type (
+	// Rotary heat exchanger. The face area is split equally between the supply and the exhaust
+	ThermalWheel struct {
+		LongName          string
+		ShortName         string
+		Length            uint64
+		Type              uint64
+		Diameter          float64 // m
+		HubDiameter       float64 // m
+		Depth             float64 // mm, along the air flow
+		HydraulicDiameter float64 // mm, of the channels
+		Porosity          float64 // open part of the face
+		RotorSpeed        float64 // rpm. Zero means DefaultRotorSpeed
+		PurgeAngle        float64 // degrees. Zero means no purge sector
+		SealLeakage       float64 // part of the supply flow leaking to the exhaust through the seals
+	}
+	// Result of the wheel with the leakages. EATR is the part of the supply made of the exhaust air,
+	// OACF is the outdoor air taken in to the supply delivered
+	ThermalWheelResult struct {
+		HeatRecoveryResult
+		FaceVelocity                 float64 // m/s
+		RotorSpeed                   float64 // rpm
+		CarryOverVolumetricFlowrate  float64 // m3/h, exhaust air carried over to the supply
+		PurgeVolumetricFlowrate      float64 // m3/h, outdoor air sent to the exhaust by the purge sector
+		OutdoorVolumetricFlowrate    float64 // m3/h, taken in from outside
+		ExhaustOutVolumetricFlowrate float64 // m3/h, discharged outside
+		EATR                         float64
+		OACF                         float64
+	}
+)
+
+func (s ThermalWheel) validate() error {
+	if s.Diameter <= s.HubDiameter || s.HubDiameter < 0 || s.Depth <= 0 || s.HydraulicDiameter <= 0 || s.Porosity <= 0 || s.Porosity >= 1 ||
+		s.PurgeAngle < 0 || s.PurgeAngle >= 180 || s.SealLeakage < 0 || s.SealLeakage >= 1 || s.Type > SorptionRotor {
+		return errors.New("invalid thermal wheel")
+	}
+	return nil
+}
+
+func (s ThermalWheel) speed() float64 {
+	if s.RotorSpeed == 0 {
+		return DefaultRotorSpeed
+	}
+	return s.RotorSpeed
+}
+
+// Returns the face area of one side (m2)
+func (s ThermalWheel) FaceArea() float64 {
+	return math.Pi / 8 * (s.Diameter*s.Diameter - s.HubDiameter*s.HubDiameter)
+}
+
+// Returns the face velocity (m/s) at the selected volumetric flowrate
+func (s ThermalWheel) FaceVelocity(VolumetricFlowrate /* m3/h */ float64) float64 {
+	return VolumetricFlowrate / SecondsInHour / s.FaceArea()
+}
+
+// Returns the heat transfer conductance (W/K) and pressure drop (Pa) of one side
+func (s ThermalWheel) side(State Air, VolumetricFlowrate float64) (ua float64, pressureDrop float64) {
+	dh := s.HydraulicDiameter / 1000
+	depth := s.Depth / 1000
+	density := State.AirDensity()
+	velocity := s.FaceVelocity(VolumetricFlowrate) / s.Porosity
+	viscosity := airViscosity(State.Temperature)
+	re := density * velocity * dh / viscosity
+	conductivity := 1000 * (DryAirHeatCapacity + State.Humidity*WaterSteamHeatCapacity) * viscosity / AirPrandtl
+	area := 4 * s.Porosity / dh * s.FaceArea() * depth // m2
+	ua = WheelLaminarNusselt * conductivity / dh * area
+	pressureDrop = (WheelLaminarFriction/re*depth/dh + WheelEntryLossFactor) * density * velocity * velocity / 2
+	return
+}
+
+// Returns the sorption factor, the storage ratio and the condensate evaporation of the rotor type
+func (s ThermalWheel) moisture() (sorption float64, storage float64, evaporation float64) {
+	switch s.Type {
+	case EnthalpyRotor:
+		return WheelEnthalpySorptionFactor, WheelEnthalpyStorageRatio, WheelEnthalpyEvaporation
+	case SorptionRotor:
+		return WheelSorptionSorptionFactor, WheelSorptionStorageRatio, WheelSorptionEvaporation
+	}
+	return 0, 0, WheelCondensationEvaporation
+}
+
+// Returns the effectiveness of the regenerator with the matrix heat capacity (W/K) by Kays and London
+func wheelEffectiveness(NTU float64, CapacityRatio float64, MatrixRatio float64) float64 {
+	if MatrixRatio <= 0 {
+		return 0
+	}
+	return math.Max(coilEffectiveness(NTU, CapacityRatio, 4)*(1-1/(9*math.Pow(MatrixRatio, 1.93))), 0)
+}
+
+// Returns the wheel result with the carry-over and purge leakages
+func (s ThermalWheel) Rate(Task HeatRecoveryTask) (out ThermalWheelResult, err error) {
+	if err = s.validate(); err != nil {
+		return
+	}
+	if err = Task.validate(); err != nil {
+		return
+	}
+	qs, qe := float64(Task.SupplyVolumetricFlowrate), float64(Task.ExhaustVolumetricFlowrate)
+	speed := s.speed()
+	out = ThermalWheelResult{HeatRecoveryResult: Task.passThrough(), RotorSpeed: speed, FaceVelocity: s.FaceVelocity(qs)}
+	supply_ua, supply_pressure_drop := s.side(Task.Outside, qs)
+	exhaust_ua, exhaust_pressure_drop := s.side(Task.Inside, qe)
+	out.SupplyPressureDrop = supply_pressure_drop
+	out.ExhaustPressureDrop = exhaust_pressure_drop
+
+	// Leakages. The rotating channels carry their volume of exhaust air over to the supply,
+	// the purge sector flushes it back with the outdoor air if it turns long enough
+	carry := s.FaceArea() * 2 * s.Depth / 1000 * s.Porosity * speed / 60 * SecondsInHour // m3/h
+	if s.PurgeAngle > 0 {
+		sector_time := s.PurgeAngle / 360 * 60 / speed
+		transit_time := s.Depth / 1000 / (out.FaceVelocity / s.Porosity)
+		flushed := math.Min(sector_time/transit_time, 1)
+		out.PurgeVolumetricFlowrate = WheelPurgeFlushingFactor * carry * flushed
+		carry *= 1 - flushed
+	}
+	out.CarryOverVolumetricFlowrate = carry
+	seal := s.SealLeakage * qs
+	out.OutdoorVolumetricFlowrate = qs - carry + out.PurgeVolumetricFlowrate + seal
+	out.ExhaustOutVolumetricFlowrate = qe - carry + out.PurgeVolumetricFlowrate + seal
+	out.EATR = carry / qs
+	out.OACF = out.OutdoorVolumetricFlowrate / qs
+
+	if Task.Inside.Temperature != Task.Outside.Temperature || Task.Inside.Humidity != Task.Outside.Humidity {
+		ms := qs * Task.Outside.AirDensity() / SecondsInHour // kg/s
+		me := qe * Task.Inside.AirDensity() / SecondsInHour
+		cs := ms * 1000 * (DryAirHeatCapacity + Task.Outside.Humidity*WaterSteamHeatCapacity) // W/K
+		ce := me * 1000 * (DryAirHeatCapacity + Task.Inside.Humidity*WaterSteamHeatCapacity)
+		cmin, cmax := math.Min(cs, ce), math.Max(cs, ce)
+		matrix := s.FaceArea() * 2 * s.Depth / 1000 * (1 - s.Porosity) * WheelMatrixDensity * WheelMatrixHeatCapacity * 1000 * speed / 60 // W/K
+		ntu := 1 / (1/supply_ua + 1/exhaust_ua) / cmin
+		temperature := wheelEffectiveness(ntu, cmin/cmax, matrix/cmin)
+		out.Supply.Temperature = Task.Outside.Temperature + temperature*cmin/cs*(Task.Inside.Temperature-Task.Outside.Temperature)
+		out.Exhaust.Temperature = Task.Inside.Temperature - (out.Supply.Temperature-Task.Outside.Temperature)*cs/ce
+
+		// Moisture: the coated rotors store it in the coating, which acts as a larger matrix for the mass
+		// transfer. The exhaust then condenses on the cold matrix and a part of the condensate evaporates
+		// into the supply on the next turn; it grows with the NTU and the hygroscopic coating holds it better
+		mmin, mmax := math.Min(ms, me), math.Max(ms, me)
+		sorption, storage, evaporation := s.moisture()
+		moisture := sorption * wheelEffectiveness(ntu, mmin/mmax, matrix/cmin*storage) * mmin / ms * (Task.Inside.Humidity - Task.Outside.Humidity) // kg/kg of the supply
+		exhaust := Task.Inside.Humidity - moisture*ms/me
+		if condensed := exhaust - out.Exhaust.SaturationHumidity(); condensed > 0 && Task.Inside.Temperature > Task.Outside.Temperature {
+			moisture += evaporation * (1 - math.Exp(-ntu)) * condensed * me / ms
+		}
+		out.Supply.Humidity = math.Min(Task.Outside.Humidity+moisture, out.Supply.SaturationHumidity())
+		out.Exhaust.Humidity = Task.Inside.Humidity - (out.Supply.Humidity-Task.Outside.Humidity)*ms/me
+		if saturation := out.Exhaust.SaturationHumidity(); out.Exhaust.Humidity > saturation {
+			out.CondensateMassFlowrate = me * (out.Exhaust.Humidity - saturation) * SecondsInHour
+			out.Exhaust.Humidity = saturation
+		}
+	}
+	// The carried over exhaust air mixes into the supply, so the efficiencies are of the supply delivered
+	if carry > 0 {
+		out.Supply, _ = MixAir(out.Supply, qs-carry, Task.Inside, carry)
+	}
+	out.HeatRecovery = math.Abs(AirHeatPowerAt(Task.Outside.Temperature, Task.Outside.Humidity, out.Supply.Temperature, qs, Task.Outside.BarometricPressure()))
+	out.TemperatureEfficiency = temperatureEfficiency(Task, out.Supply)
+	if Task.Inside.Humidity != Task.Outside.Humidity {
+		out.HumidityEfficiency = (out.Supply.Humidity - Task.Outside.Humidity) / (Task.Inside.Humidity - Task.Outside.Humidity)
+	}
+	return
+}
+
+// Returns the wheel result without the leakage figures
+func (s ThermalWheel) Calculate(Task HeatRecoveryTask) (HeatRecoveryResult, error) {
+	out, err := s.Rate(Task)
+	return out.HeatRecoveryResult, err
+}
+
+// Returns the wheel result for both seasons
+func (s ThermalWheel) Calculate2(Task HeatRecoveryTask2) (out HeatRecoveryResult2, err error) {
+	out = HeatRecoveryResult2{LongName: s.LongName, ShortName: s.ShortName, Length: s.Length}
+	if out.Summer, err = s.Calculate(Task.Summer); err != nil {
+		return
+	}
+	out.Winter, err = s.Calculate(Task.Winter)
+	return
+}
@@ -0,0 +1,57 @@
+package HVAC
+
+import (
+	"math"
+	"testing"
+)
+
+var testWheel = ThermalWheel{Type: EnthalpyRotor, Diameter: 1.2, HubDiameter: .1, Depth: 200, HydraulicDiameter: 1.8, Porosity: .85, PurgeAngle: 5, SealLeakage: .02}
+
+func TestThermalWheel(t *testing.T) {
+	task := testHeatRecoveryTask()
+	// The purge and seal leakages carry some air past the matrix, so the balance is looser than for the plates
+	result := checkHeatRecovery(t, testWheel, 2e-2)
+	for _, season := range []struct {
+		name   string
+		task   HeatRecoveryTask
+		result HeatRecoveryResult
+	}{{"summer", task.Summer, result.Summer}, {"winter", task.Winter, result.Winter}} {
+		r := season.result
+		if r.HumidityEfficiency <= 0 {
+			t.Errorf("%v: humidity efficiency %v, want the enthalpy rotor to pass moisture", season.name, r.HumidityEfficiency)
+		}
+		// Moisture goes the same way as the heat: from the indoor air in winter, to the exhaust in summer
+		if (r.Supply.Humidity-season.task.Outside.Humidity)*(season.task.Inside.Humidity-season.task.Outside.Humidity) <= 0 {
+			t.Errorf("%v: supply humidity %v kg/kg does not move from %v towards %v kg/kg", season.name,
+				r.Supply.Humidity, season.task.Outside.Humidity, season.task.Inside.Humidity)
+		}
+		// The heat recovery of the wheel is the sensible part of what the supply gains
+		sensible := AirHeatPowerAt(season.task.Outside.Temperature, season.task.Outside.Humidity, r.Supply.Temperature,
+			float64(season.task.SupplyVolumetricFlowrate), season.task.Outside.BarometricPressure())
+		if math.Abs(r.HeatRecovery-sensible) > 1e-6*sensible {
+			t.Errorf("%v: heat recovery %v kW, want the sensible %v kW", season.name, r.HeatRecovery, sensible)
+		}
+	}
+}
+
+func TestThermalWheelRotorTypes(t *testing.T) {
+	task := testHeatRecoveryTask().Winter
+	efficiencies := map[float64]uint64{}
+	previous := 0.
+	for _, rotor := range []uint64{CondensationRotor, EnthalpyRotor, SorptionRotor} {
+		wheel := testWheel
+		wheel.Type = rotor
+		result, err := wheel.Calculate(task)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.HumidityEfficiency <= previous {
+			t.Errorf("rotor type %v: humidity efficiency %v is not above %v", rotor, result.HumidityEfficiency, previous)
+		}
+		if other, ok := efficiencies[result.HumidityEfficiency]; ok {
+			t.Errorf("rotor types %v and %v give the same humidity efficiency %v", other, rotor, result.HumidityEfficiency)
+		}
+		efficiencies[result.HumidityEfficiency] = rotor
+		previous = result.HumidityEfficiency
+	}
+}
@@ -0,0 +1,84 @@
+package HVAC
+
+// Typed physical quantities. Each type stores the value in the unit used across the library,
+// use the constructors and the methods of the same name to convert from and to other units
+type (
+	Temperature    float64 // ºC
+	Pressure       float64 // Pa
+	VolumetricFlow float64 // m3/h
+	MassFlow       float64 // kg/h
+	Power          float64 // kW
+	Humidity       float64 // kg/kg of dry air, as in Air.Humidity
+)
+
+const (
+	PascalsInInchOfWater        = 249.08891  // Pa
+	PascalsInPSI                = 6894.75729 // Pa
+	CubicMetersPerHourInCFM     = 1.69901082 // m3/h
+	KilogramsInPound            = .45359237  // kg
+	WattsInBTUPerHour           = .29307107  // W
+	KilowattsInRefrigerationTon = 3.51685284 // kW
+	GrainsInPound               = 7000
+)
+
+func Celsius(v float64) Temperature    { return Temperature(v) }
+func Fahrenheit(v float64) Temperature { return Temperature((v - 32) * 5 / 9) }
+func Kelvin(v float64) Temperature     { return Temperature(v - CelsiusToKelvinDifference) }
+
+func (s Temperature) Celsius() float64    { return float64(s) }
+func (s Temperature) Fahrenheit() float64 { return float64(s)*9/5 + 32 }
+func (s Temperature) Kelvin() float64     { return float64(s) + CelsiusToKelvinDifference }
+
+func Pascal(v float64) Pressure        { return Pressure(v) }
+func KiloPascal(v float64) Pressure    { return Pressure(v * 1000) }
+func InchesOfWater(v float64) Pressure { return Pressure(v * PascalsInInchOfWater) }
+func PSI(v float64) Pressure           { return Pressure(v * PascalsInPSI) }
+
+func (s Pressure) Pascal() float64        { return float64(s) }
+func (s Pressure) KiloPascal() float64    { return float64(s) / 1000 }
+func (s Pressure) InchesOfWater() float64 { return float64(s) / PascalsInInchOfWater }
+func (s Pressure) PSI() float64           { return float64(s) / PascalsInPSI }
+
+func CubicMetersPerHour(v float64) VolumetricFlow { return VolumetricFlow(v) }
+func LitersPerSecond(v float64) VolumetricFlow    { return VolumetricFlow(v * SecondsInHour / 1000) }
+func CFM(v float64) VolumetricFlow                { return VolumetricFlow(v * CubicMetersPerHourInCFM) }
+
+func (s VolumetricFlow) CubicMetersPerHour() float64 { return float64(s) }
+func (s VolumetricFlow) LitersPerSecond() float64    { return float64(s) * 1000 / SecondsInHour }
+func (s VolumetricFlow) CFM() float64                { return float64(s) / CubicMetersPerHourInCFM }
+
+func KilogramsPerHour(v float64) MassFlow   { return MassFlow(v) }
+func KilogramsPerSecond(v float64) MassFlow { return MassFlow(v * SecondsInHour) }
+func PoundsPerHour(v float64) MassFlow      { return MassFlow(v * KilogramsInPound) }
+
+func (s MassFlow) KilogramsPerHour() float64   { return float64(s) }
+func (s MassFlow) KilogramsPerSecond() float64 { return float64(s) / SecondsInHour }
+func (s MassFlow) PoundsPerHour() float64      { return float64(s) / KilogramsInPound }
+
+func Kilowatt(v float64) Power         { return Power(v) }
+func Watt(v float64) Power             { return Power(v / 1000) }
+func BTUPerHour(v float64) Power       { return Power(v * WattsInBTUPerHour / 1000) }
+func RefrigerationTon(v float64) Power { return Power(v * KilowattsInRefrigerationTon) }
+
+func (s Power) Kilowatt() float64         { return float64(s) }
+func (s Power) Watt() float64             { return float64(s) * 1000 }
+func (s Power) BTUPerHour() float64       { return float64(s) * 1000 / WattsInBTUPerHour }
+func (s Power) RefrigerationTon() float64 { return float64(s) / KilowattsInRefrigerationTon }
+
+func KilogramsPerKilogram(v float64) Humidity { return Humidity(v) }
+func GramsPerKilogram(v float64) Humidity     { return Humidity(v / 1000) }
+func GrainsPerPound(v float64) Humidity       { return Humidity(v / GrainsInPound) }
+
+func (s Humidity) KilogramsPerKilogram() float64 { return float64(s) }
+func (s Humidity) GramsPerKilogram() float64     { return float64(s) * 1000 }
+func (s Humidity) GrainsPerPound() float64       { return float64(s) * GrainsInPound }
+
+// Same as AirHeatPower, but with typed quantities
+func AirHeatPowerOf(InletTemperature Temperature, Moisture Humidity, OutgoingTemperature Temperature, VolumetricFlowrate VolumetricFlow) Power {
+	return Power(AirHeatPower(float64(InletTemperature), float64(Moisture), float64(OutgoingTemperature), float64(VolumetricFlowrate)))
+}
+
+// Same as AirHeatOutgoingTemperature, but with typed quantities
+func AirHeatOutgoingTemperatureOf(HeatingPower Power, VolumetricFlowrate VolumetricFlow, InletTemperature Temperature, Moisture Humidity) Temperature {
+	return Temperature(AirHeatOutgoingTemperature(float64(HeatingPower), float64(VolumetricFlowrate), float64(InletTemperature), float64(Moisture)))
+}
@@ -0,0 +1,266 @@
+package HVAC
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	ErrNaN            = errors.New("value is not a number")
+	ErrOutOfRange     = errors.New("value is out of range")
+	ErrSupersaturated = errors.New("air is supersaturated")
+)
+
+// Invalid air state. Err is ErrNaN, ErrOutOfRange or ErrSupersaturated
+type AirError struct {
+	Air   Air
+	Field string
+	Err   error
+}
+
+func (e *AirError) Error() string {
+	return fmt.Sprintf("invalid air state (%v ºC, %v kg/kg, %v Pa): %v: %v", e.Air.Temperature, e.Air.Humidity, e.Air.BarometricPressure(), e.Field, e.Err)
+}
+
+func (e *AirError) Unwrap() error {
+	return e.Err
+}
+
+// Checks that the air state can exist: the values are numbers, the temperature is in the range
+// of the saturation formula, the humidity is not negative and not above saturation
+func (s *Air) Validate() error {
+	for _, v := range []struct {
+		field string
+		value float64
+	}{{"Temperature", s.Temperature}, {"Humidity", s.Humidity}, {"Pressure", s.Pressure}} {
+		if math.IsNaN(v.value) || math.IsInf(v.value, 0) {
+			return &AirError{*s, v.field, ErrNaN}
+		}
+	}
+	if min, max := saturationFormula.Range(); s.Temperature < min || s.Temperature > max {
+		return &AirError{*s, "Temperature", ErrOutOfRange}
+	}
+	if s.Pressure < 0 {
+		return &AirError{*s, "Pressure", ErrOutOfRange}
+	}
+	if s.Humidity < 0 {
+		return &AirError{*s, "Humidity", ErrOutOfRange}
+	}
+	if s.Humidity > s.SaturationHumidity()*(1+1e-9) {
+		return &AirError{*s, "Humidity", ErrSupersaturated}
+	}
+	return nil
+}
+
+// Returns the validated air state at normal pressure
+func NewAir(Temperature /* ºC */ float64, Humidity /* kg/kg */ float64) (Air, error) {
+	return NewAirAt(Temperature, Humidity, NormalPressure)
+}
+
+// Same as NewAir, but at the selected barometric pressure
+func NewAirAt(Temperature /* ºC */ float64, Humidity /* kg/kg */ float64, Pressure /* Pa */ float64) (Air, error) {
+	out := Air{Temperature: Temperature, Humidity: Humidity, Pressure: Pressure}
+	return out, out.Validate()
+}
+
+// Returns the validated air state at normal pressure from the temperature and relative humidity
+func NewAirRH(Temperature /* ºC */ float64, HumidityRatio /* % */ float64) (Air, error) {
+	return NewAirRHAt(Temperature, HumidityRatio, NormalPressure)
+}
+
+// Same as NewAirRH, but at the selected barometric pressure
+func NewAirRHAt(Temperature /* ºC */ float64, HumidityRatio /* % */ float64, Pressure /* Pa */ float64) (Air, error) {
+	out := Air{Temperature: Temperature, Pressure: Pressure}
+	if err := out.SetHumidityRatio(HumidityRatio); err != nil {
+		return out, err
+	}
+	return out, out.Validate()
+}
+
+// Checks a scalar argument of a calculation
+func validateValue[T anyFloat](Field string, Value T, Min float64, Max float64) error {
+	v := float64(Value)
+	if math.IsNaN(v) {
+		return fmt.Errorf("%v: %w", Field, ErrNaN)
+	}
+	if v < Min || v > Max {
+		return fmt.Errorf("%v: %v: %w", Field, v, ErrOutOfRange)
+	}
+	return nil
+}
+
+// Same as AirHeatPower, but the inlet and outgoing air states and the flowrate are validated
+func AirHeatPowerChecked[T anyFloat](InletTemperature /* ºC */ T, Humidity /* kg/kg */ T, OutgoingTemperature /* ºC */ T, VolumetricFlowrate /* m3/h */ T) (T, error) {
+	return AirHeatPowerAtChecked(InletTemperature, Humidity, OutgoingTemperature, VolumetricFlowrate, NormalPressure)
+}
+
+// Same as AirHeatPowerAt, but the inlet and outgoing air states and the flowrate are validated
+func AirHeatPowerAtChecked[T anyFloat](InletTemperature /* ºC */ T, Humidity /* kg/kg */ T, OutgoingTemperature /* ºC */ T, VolumetricFlowrate /* m3/h */ T, Pressure /* Pa */ T) (T, error) {
+	for _, t := range []T{InletTemperature, OutgoingTemperature} {
+		a := Air{Temperature: float64(t), Humidity: float64(Humidity), Pressure: float64(Pressure)}
+		if err := a.Validate(); err != nil {
+			return 0, err
+		}
+	}
+	if err := validateValue("VolumetricFlowrate", VolumetricFlowrate, 0, math.Inf(1)); err != nil {
+		return 0, err
+	}
+	return AirHeatPowerAt(InletTemperature, Humidity, OutgoingTemperature, VolumetricFlowrate, Pressure), nil
+}
+
+// Same as AirHeatOutgoingTemperature, but the inlet and outgoing air states and the flowrate are validated
+func AirHeatOutgoingTemperatureChecked[T anyFloat](HeatingPower /* kW */ T, VolumetricFlowrate /* m3/h */ T, InletTemperature /* ºC */ T, Humidity /* kg/kg */ T) (T, error) {
+	return AirHeatOutgoingTemperatureAtChecked(HeatingPower, VolumetricFlowrate, InletTemperature, Humidity, NormalPressure)
+}
+
+// Same as AirHeatOutgoingTemperatureAt, but the inlet and outgoing air states and the flowrate are validated
+func AirHeatOutgoingTemperatureAtChecked[T anyFloat](HeatingPower /* kW */ T, VolumetricFlowrate /* m3/h */ T, InletTemperature /* ºC */ T, Humidity /* kg/kg */ T, Pressure /* Pa */ T) (T, error) {
+	if err := validateValue("HeatingPower", HeatingPower, math.Inf(-1), math.Inf(1)); err != nil {
+		return 0, err
+	}
+	if err := validateValue("VolumetricFlowrate", VolumetricFlowrate, math.SmallestNonzeroFloat64, math.Inf(1)); err != nil {
+		return 0, err
+	}
+	inlet := Air{Temperature: float64(InletTemperature), Humidity: float64(Humidity), Pressure: float64(Pressure)}
+	if err := inlet.Validate(); err != nil {
+		return 0, err
+	}
+	out := AirHeatOutgoingTemperatureAt(HeatingPower, VolumetricFlowrate, InletTemperature, Humidity, Pressure)
+	outgoing := Air{Temperature: float64(out), Humidity: float64(Humidity), Pressure: float64(Pressure)}
+	return out, outgoing.Validate()
+}
+
+// Same as CoolingCondensationFlowrate, but the arguments are validated
+func CoolingCondensationFlowrateChecked[T anyFloat](InletHumidity T, OutgoingTemperature T, VolumetricFlowrate T) (T, T, error) {
+	if err := validateValue("InletHumidity", InletHumidity, 0, math.Inf(1)); err != nil {
+		return 0, 0, err
+	}
+	if err := validateValue("VolumetricFlowrate", VolumetricFlowrate, 0, math.Inf(1)); err != nil {
+		return 0, 0, err
+	}
+	outgoing := Air{Temperature: float64(OutgoingTemperature)}
+	if err := outgoing.Validate(); err != nil {
+		return 0, 0, err
+	}
+	mass, volume := CoolingCondensationFlowrate(InletHumidity, OutgoingTemperature, VolumetricFlowrate)
+	return mass, volume, nil
+}
+
+// Same as CoolingCondensationFlowrateAt, but the inlet air state and the flowrate are validated
+func CoolingCondensationFlowrateAtChecked[T anyFloat](InletHumidity /* kg/kg */ T, OutgoingTemperature /* ºC */ T, VolumetricFlowrate /* m3/h */ T, Pressure /* Pa */ T) (T, T, error) {
+	if err := validateValue("VolumetricFlowrate", VolumetricFlowrate, 0, math.Inf(1)); err != nil {
+		return 0, 0, err
+	}
+	outgoing := Air{Temperature: float64(OutgoingTemperature), Pressure: float64(Pressure)}
+	if err := outgoing.Validate(); err != nil {
+		return 0, 0, err
+	}
+	if err := validateValue("InletHumidity", InletHumidity, 0, math.Inf(1)); err != nil {
+		return 0, 0, err
+	}
+	mass, volume := CoolingCondensationFlowrateAt(InletHumidity, OutgoingTemperature, VolumetricFlowrate, Pressure)
+	return mass, volume, nil
+}
+
+// Same as MixAir, but both streams and flowrates are validated
+func MixAirChecked(a Air, qa /* m3/h */ float64, b Air, qb /* m3/h */ float64) (Mixed Air, Fog float64 /* kg/kg */, err error) {
+	for _, s := range []Air{a, b} {
+		if err = s.Validate(); err != nil {
+			return
+		}
+	}
+	for _, q := range []float64{qa, qb} {
+		if err = validateValue("VolumetricFlowrate", q, 0, math.Inf(1)); err != nil {
+			return
+		}
+	}
+	Mixed, Fog = MixAir(a, qa, b, qb)
+	return
+}
+
+// Same as AirDensityAt, but the air state is validated
+func AirDensityAtChecked[T anyFloat](Temperature /* ºC */ T, Pressure /* Pa */ T) (T, error) {
+	a := Air{Temperature: float64(Temperature), Pressure: float64(Pressure)}
+	if err := a.Validate(); err != nil {
+		return T(math.NaN()), err
+	}
+	return AirDensityAt(Temperature, Pressure), nil
+}
+
+// Same as VaporDensity, but the temperature is validated
+func VaporDensityChecked[T anyFloat](Temperature /* ºC */ T) (T, error) {
+	if _, err := VaporPressureChecked(Temperature); err != nil {
+		return T(math.NaN()), err
+	}
+	return VaporDensity(Temperature), nil
+}
+
+// Same as PartialPressure, but the temperature and the humidity ratio are validated
+func PartialPressureChecked[T anyFloat](Temperature /* ºC */ T, HumidityRatio /* % */ T) (T, error) {
+	if err := validateValue("HumidityRatio", HumidityRatio, 0, 100); err != nil {
+		return T(math.NaN()), err
+	}
+	if _, err := VaporPressureChecked(Temperature); err != nil {
+		return T(math.NaN()), err
+	}
+	return PartialPressure(Temperature, HumidityRatio), nil
+}
+
+// Same as SaturationHumidity, but the temperature is validated
+func SaturationHumidityChecked[T anyFloat](Temperature /* ºC */ T) (T, error) {
+	return SaturationHumidityAtChecked(Temperature, NormalPressure)
+}
+
+// Same as SaturationHumidityAt, but the air state is validated
+func SaturationHumidityAtChecked[T anyFloat](Temperature /* ºC */ T, Pressure /* Pa */ T) (T, error) {
+	a := Air{Temperature: float64(Temperature), Pressure: float64(Pressure)}
+	if err := a.Validate(); err != nil {
+		return T(math.NaN()), err
+	}
+	return SaturationHumidityAt(Temperature, Pressure), nil
+}
+
+// Same as AirEnthalpy, but the humidity is validated against the saturation at normal pressure
+func AirEnthalpyChecked[T anyFloat](Temperature /* ºC */ T, Humidity /* kg/kg */ T) (T, error) {
+	a := Air{Temperature: float64(Temperature), Humidity: float64(Humidity)}
+	if err := a.Validate(); err != nil {
+		return T(math.NaN()), err
+	}
+	return AirEnthalpy(Temperature, Humidity), nil
+}
+
+// Same as Air.HumidityRatio, but the air state is validated
+func (s *Air) HumidityRatioChecked() (float64 /* % */, error) {
+	if err := s.Validate(); err != nil {
+		return math.NaN(), err
+	}
+	return s.HumidityRatio(), nil
+}
+
+// Same as Air.PartialPressure, but the air state is validated
+func (s *Air) PartialPressureChecked() (float64 /* kPa */, error) {
+	if err := s.Validate(); err != nil {
+		return math.NaN(), err
+	}
+	return s.PartialPressure(), nil
+}
+
+// Same as Air.DewPoint, but the air state is validated and the dew point below -100 ºC is reported
+func (s *Air) DewPointChecked() (float64 /* ºC */, error) {
+	if err := s.Validate(); err != nil {
+		return math.NaN(), err
+	}
+	if s.PartialPressure() <= VaporPressure(-100.) {
+		return math.NaN(), &AirError{*s, "DewPoint", ErrOutOfRange}
+	}
+	return s.DewPoint(), nil
+}
+
+// Same as Air.WetBulb, but the air state is validated
+func (s *Air) WetBulbChecked() (float64 /* ºC */, error) {
+	if err := s.Validate(); err != nil {
+		return math.NaN(), err
+	}
+	return s.WetBulb(), nil
+}
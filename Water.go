@@ -0,0 +1,132 @@
+package HVAC
+
+import (
+	"fmt"
+	"math"
+)
+
+// Range of the water property fits
+const (
+	WaterMinTemperature = 0   // ºC
+	WaterMaxTemperature = 100 // ºC
+)
+
+// Returns the density of water (kg/m3), valid from WaterMinTemperature to WaterMaxTemperature
+func WaterDensity[T anyFloat](t T) T {
+	x := float64(t)
+	return T(1000 * (0.999922 + 0.0000475377*x - 7.34753*math.Pow10(-6)*math.Pow(x, 2) + 3.92894*math.Pow10(-8)*math.Pow(x, 3) - 1.2144*math.Pow10(-10)*math.Pow(x, 4)))
+}
+
+// Returns the specific heat capacity of water (kJ/kg K), valid from WaterMinTemperature to WaterMaxTemperature
+func WaterHeatCapacity[T anyFloat](t T) T {
+	x := float64(t)
+	y := 4.21701568841111 - 0.007849993116176771*x + 0.0010067730629546086*math.Pow(x, 2) - 0.00006957457567073951*math.Pow(x, 3) + 2.605607116793847*math.Pow10(-6)*math.Pow(x, 4) - 5.609144300760924*math.Pow10(-8)*math.Pow(x, 5) + 7.141795677520566*math.Pow10(-10)*math.Pow(x, 6) - 5.286838922592877*math.Pow10(-12)*math.Pow(x, 7) + 2.0881591874928128*math.Pow10(-14)*math.Pow(x, 8) - 3.366302230773775*math.Pow10(-17)*math.Pow(x, 9)
+	return T(y)
+}
+
+// Returns the dynamic viscosity of water (Pa s) by the Vogel equation, valid from WaterMinTemperature to WaterMaxTemperature
+func WaterViscosity[T anyFloat](t T) T {
+	return T(2.414e-5 * math.Pow(10, 247.8/(float64(t)+CelsiusToKelvinDifference-140)))
+}
+
+// Returns the kinematic viscosity of water (m2/s), valid from WaterMinTemperature to WaterMaxTemperature
+func WaterKinematicViscosity[T anyFloat](t T) T {
+	return WaterViscosity(t) / WaterDensity(t)
+}
+
+// Returns the thermal conductivity of water (W/m K), valid from WaterMinTemperature to WaterMaxTemperature
+func WaterThermalConductivity[T anyFloat](t T) T {
+	k := float64(t) + CelsiusToKelvinDifference
+	return T(-.5752 + 6.397e-3*k - 8.151e-6*k*k)
+}
+
+// Returns the Prandtl number of water, valid from WaterMinTemperature to WaterMaxTemperature
+func WaterPrandtl[T anyFloat](t T) T {
+	return 1000 * WaterHeatCapacity(t) * WaterViscosity(t) / WaterThermalConductivity(t)
+}
+
+// Checks that the temperature is in the range of the water property fits
+func checkWaterTemperature[T anyFloat](t T) error {
+	if math.IsNaN(float64(t)) {
+		return fmt.Errorf("water temperature: %w", ErrNaN)
+	}
+	if t < WaterMinTemperature || t > WaterMaxTemperature {
+		return fmt.Errorf("%w: water %v ºC is out of %v..%v ºC", ErrTemperatureOutOfRange, t, WaterMinTemperature, WaterMaxTemperature)
+	}
+	return nil
+}
+
+// Returns water property by the selected function if the temperature is in the valid range
+func checkedWaterProperty[T anyFloat](Property func(T) T, t T) (T, error) {
+	if err := checkWaterTemperature(t); err != nil {
+		return T(math.NaN()), err
+	}
+	return Property(t), nil
+}
+
+// Same as WaterDensity, but reports the temperature out of the valid range
+func WaterDensityChecked[T anyFloat](t T) (T, error) {
+	return checkedWaterProperty(WaterDensity[T], t)
+}
+
+// Same as WaterHeatCapacity, but reports the temperature out of the valid range
+func WaterHeatCapacityChecked[T anyFloat](t T) (T, error) {
+	return checkedWaterProperty(WaterHeatCapacity[T], t)
+}
+
+// Same as WaterViscosity, but reports the temperature out of the valid range
+func WaterViscosityChecked[T anyFloat](t T) (T, error) {
+	return checkedWaterProperty(WaterViscosity[T], t)
+}
+
+// Same as WaterKinematicViscosity, but reports the temperature out of the valid range
+func WaterKinematicViscosityChecked[T anyFloat](t T) (T, error) {
+	return checkedWaterProperty(WaterKinematicViscosity[T], t)
+}
+
+// Same as WaterThermalConductivity, but reports the temperature out of the valid range
+func WaterThermalConductivityChecked[T anyFloat](t T) (T, error) {
+	return checkedWaterProperty(WaterThermalConductivity[T], t)
+}
+
+// Same as WaterPrandtl, but reports the temperature out of the valid range
+func WaterPrandtlChecked[T anyFloat](t T) (T, error) {
+	return checkedWaterProperty(WaterPrandtl[T], t)
+}
+
+// Same as WaterHeatPower, but reports the water temperatures out of the valid range
+func WaterHeatPowerChecked[T anyFloat](InletTemperature /* ºC */ T, OutgoingTemperature /* ºC */ T, VolumetricFlowrate /* dm3/h */ T) (T, error) {
+	for _, t := range []T{InletTemperature, OutgoingTemperature} {
+		if err := checkWaterTemperature(t); err != nil {
+			return T(math.NaN()), err
+		}
+	}
+	if err := validateValue("VolumetricFlowrate", VolumetricFlowrate, 0, math.Inf(1)); err != nil {
+		return T(math.NaN()), err
+	}
+	return WaterHeatPower(InletTemperature, OutgoingTemperature, VolumetricFlowrate), nil
+}
+
+// Same as WaterHeatTemperature, but reports the water temperature out of the valid range
+func WaterHeatTemperatureChecked[T anyFloat](HeatingPower /* kW */ T, VolumetricFlowrate /* dm3/h */ T, InletTemperature /* ºC */ T) (T, error) {
+	if err := checkWaterTemperature(InletTemperature); err != nil {
+		return T(math.NaN()), err
+	}
+	if err := validateValue("VolumetricFlowrate", VolumetricFlowrate, math.SmallestNonzeroFloat64, math.Inf(1)); err != nil {
+		return T(math.NaN()), err
+	}
+	return WaterHeatTemperature(HeatingPower, VolumetricFlowrate, InletTemperature), nil
+}
+
+// Same as WaterHeatVolumetricFlowRate, but reports the water temperatures out of the valid range
+func WaterHeatVolumetricFlowRateChecked[T anyFloat](HeatingPower /* kW */ T, InletTemperature /* ºC */ T, OutgoingTemperature /* ºC */ T) (T, error) {
+	for _, t := range []T{InletTemperature, OutgoingTemperature} {
+		if err := checkWaterTemperature(t); err != nil {
+			return T(math.NaN()), err
+		}
+	}
+	if InletTemperature == OutgoingTemperature {
+		return T(math.NaN()), fmt.Errorf("water temperature difference: %w", ErrOutOfRange)
+	}
+	return WaterHeatVolumetricFlowRate(HeatingPower, InletTemperature, OutgoingTemperature), nil
+}
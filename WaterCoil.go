@@ -0,0 +1,343 @@
+package HVAC
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	FinThermalConductivity = 200 // W/m K, aluminium
+	AirPrandtl             = .71
+	WetCoilPressureFactor  = 1.3 // air pressure drop of the wet coil to the dry one
+	TubeLaminarReynolds    = 2300
+	TubeTurbulentReynolds  = 3000
+)
+
+type (
+	// Finned-tube water coil with staggered tubes. Tubes is the number of tubes in a row,
+	// the water flows in Circuits parallel circuits through all Rows*Tubes tubes
+	WaterCoil struct {
+		LongName          string
+		ShortName         string
+		Length            uint64
+		Rows              uint64
+		Tubes             uint64
+		Circuits          uint64
+		TubeLength        float64 // m, the width of the face
+		TubeOuterDiameter float64 // mm
+		TubeInnerDiameter float64 // mm
+		TubePitch         float64 // mm, across the air flow
+		RowPitch          float64 // mm, along the air flow
+		FinPitch          float64 // mm
+		FinThickness      float64 // mm
+	}
+	// Result of the coil at the selected air and water inlet conditions.
+	// Power is positive for heating and negative for cooling
+	CoilRating struct {
+		Inlet                    Air
+		Outgoing                 Air
+		VolumetricFlowrate       float64 // m3/h
+		Power                    float64 // kW
+		WaterInletTemperature    float64 // ºC
+		WaterOutgoingTemperature float64 // ºC
+		WaterVolumetricFlowrate  float64 // dm3/h
+		PressureDrop             float64 // Pa
+		WaterPressureDrop        float64 // kPa
+		CondensateMassFlowrate   float64 // kg/h
+		Wet                      bool
+	}
+	coilSurface struct {
+		outer, fin, inner, minFlow float64 // m2
+	}
+)
+
+// Returns the face area of the coil (m2)
+func (s WaterCoil) FaceArea() float64 {
+	return float64(s.Tubes) * s.TubePitch / 1000 * s.TubeLength
+}
+
+// Returns the face velocity (m/s) at the selected volumetric flowrate
+func (s WaterCoil) FaceVelocity(VolumetricFlowrate /* m3/h */ float64) float64 {
+	return VolumetricFlowrate / SecondsInHour / s.FaceArea()
+}
+
+func (s WaterCoil) validate() error {
+	if s.Rows == 0 || s.Tubes == 0 || s.Circuits == 0 || s.TubeLength <= 0 || s.FinPitch <= s.FinThickness ||
+		s.TubeInnerDiameter <= 0 || s.TubeOuterDiameter <= s.TubeInnerDiameter || s.TubePitch <= s.TubeOuterDiameter || s.RowPitch <= 0 {
+		return errors.New("invalid coil geometry")
+	}
+	return nil
+}
+
+func (s WaterCoil) surface() (out coilSurface) {
+	d := s.TubeOuterDiameter / 1000
+	fp := s.FinPitch / 1000
+	ft := s.FinThickness / 1000
+	tubes := float64(s.Rows * s.Tubes)
+	out.fin = 2 * (s.TubePitch*s.RowPitch/1e6 - math.Pi*d*d/4) * s.TubeLength / fp * tubes
+	out.outer = out.fin + math.Pi*d*s.TubeLength*(1-ft/fp)*tubes
+	out.inner = math.Pi * s.TubeInnerDiameter / 1000 * s.TubeLength * tubes
+	out.minFlow = s.FaceArea() * (1 - s.TubeOuterDiameter/s.TubePitch) * (1 - ft/fp)
+	return
+}
+
+// Returns the dynamic viscosity of air (Pa s) by the Sutherland formula
+func airViscosity(Temperature /* ºC */ float64) float64 {
+	t := Temperature + CelsiusToKelvinDifference
+	return 1.458e-6 * math.Pow(t, 1.5) / (t + 110.4)
+}
+
+// Returns the pressure drop (Pa) of the dry coil at the selected volumetric flowrate
+func (s WaterCoil) PressureDrop(Inlet Air, VolumetricFlowrate /* m3/h */ float64) float64 {
+	surface := s.surface()
+	_, _, friction, velocity := s.airSide(Inlet, VolumetricFlowrate, surface)
+	return friction * surface.outer / surface.minFlow * velocity * velocity / (2 * Inlet.AirDensity())
+}
+
+// Returns the air side heat transfer coefficient (W/m2 K), the overall surface efficiency,
+// the fanning friction factor and the mass velocity in the minimum flow area (kg/m2 s)
+func (s WaterCoil) airSide(Inlet Air, VolumetricFlowrate float64, Surface coilSurface) (h float64, efficiency float64, friction float64, velocity float64) {
+	d := s.TubeOuterDiameter / 1000
+	velocity = VolumetricFlowrate * Inlet.AirDensity() / SecondsInHour / Surface.minFlow
+	re := velocity * d / airViscosity(Inlet.Temperature)
+	j := .14 * math.Pow(re, -.328) * math.Pow(s.TubePitch/s.RowPitch, -.502) * math.Pow(s.FinPitch/s.TubeOuterDiameter, .0312)
+	friction = 3.5 * j
+	h = j * velocity * 1000 * (DryAirHeatCapacity + Inlet.Humidity*WaterSteamHeatCapacity) / math.Pow(AirPrandtl, 2./3)
+	// Schmidt's equivalent circular fin for staggered tubes
+	ro := d / 2
+	xm := s.TubePitch / 2000
+	xl := math.Hypot(s.TubePitch/2000, s.RowPitch/1000) / 2
+	ratio := 1.27 * xm / ro * math.Sqrt(xl/xm-.3)
+	phi := (ratio - 1) * (1 + .35*math.Log(ratio))
+	mr := math.Sqrt(2*h/(FinThermalConductivity*s.FinThickness/1000)) * ro * phi
+	fin := math.Tanh(mr) / mr
+	efficiency = 1 - Surface.fin/Surface.outer*(1-fin)
+	return
+}
+
+// Returns the water side heat transfer coefficient (W/m2 K) and pressure drop (kPa)
+func (s WaterCoil) waterSide(Fluid Fluid, Temperature float64, VolumetricFlowrate /* dm3/h */ float64) (h float64, pressureDrop float64) {
+	di := s.TubeInnerDiameter / 1000
+	velocity := VolumetricFlowrate / 1000 / SecondsInHour / float64(s.Circuits) / (math.Pi * di * di / 4)
+	density := Fluid.Density(Temperature)
+	viscosity := Fluid.Viscosity(Temperature)
+	conductivity := Fluid.ThermalConductivity(Temperature)
+	pr := 1000 * Fluid.HeatCapacity(Temperature) * viscosity / conductivity
+	re := density * velocity * di / viscosity
+	// Gnielinski for the turbulent flow, laminar below TubeLaminarReynolds and linear transition in between
+	nu := func(re float64) float64 {
+		f := math.Pow(.79*math.Log(re)-1.64, -2)
+		return f / 8 * (re - 1000) * pr / (1 + 12.7*math.Sqrt(f/8)*(math.Pow(pr, 2./3)-1))
+	}
+	var n float64
+	switch {
+	case re < TubeLaminarReynolds:
+		n = 3.66
+	case re < TubeTurbulentReynolds:
+		n = 3.66 + (nu(TubeTurbulentReynolds)-3.66)*(re-TubeLaminarReynolds)/(TubeTurbulentReynolds-TubeLaminarReynolds)
+	default:
+		n = nu(re)
+	}
+	h = n * conductivity / di
+	friction := 64 / math.Max(re, 1)
+	if re >= TubeLaminarReynolds {
+		friction = .3164 * math.Pow(re, -.25)
+	}
+	tubes := float64(s.Rows*s.Tubes) / float64(s.Circuits)
+	pressureDrop = (friction*tubes*s.TubeLength/di + .8*(tubes-1)) * density * velocity * velocity / 2 / 1000
+	return
+}
+
+// Returns the water flowrate (dm3/h) at which the flow in the tubes becomes turbulent
+func (s WaterCoil) turbulentWaterFlowrate(Fluid Fluid, Temperature /* ºC */ float64) float64 {
+	di := s.TubeInnerDiameter / 1000
+	velocity := TubeTurbulentReynolds * Fluid.Viscosity(Temperature) / Fluid.Density(Temperature) / di
+	return velocity * math.Pi * di * di / 4 * float64(s.Circuits) * 1000 * SecondsInHour
+}
+
+// Returns the effectiveness of the coil. Coils with 4 and more rows are treated as counterflow,
+// others as crossflow with both fluids unmixed
+func coilEffectiveness(NTU float64, CapacityRatio float64, Rows uint64) float64 {
+	if CapacityRatio < 1e-9 {
+		return 1 - math.Exp(-NTU)
+	}
+	if Rows < 4 {
+		return 1 - math.Exp(math.Pow(NTU, .22)/CapacityRatio*(math.Exp(-CapacityRatio*math.Pow(NTU, .78))-1))
+	}
+	if math.Abs(1-CapacityRatio) < 1e-9 {
+		return NTU / (1 + NTU)
+	}
+	e := math.Exp(-NTU * (1 - CapacityRatio))
+	return (1 - e) / (1 - CapacityRatio*e)
+}
+
+// Returns the saturated air enthalpy (kJ/kg) at the selected temperature and pressure
+func saturationEnthalpy(Temperature float64, Pressure float64) float64 {
+	return AirEnthalpy(Temperature, SaturationHumidityAt(Temperature, Pressure))
+}
+
+// Returns the outgoing air of the wet coil that takes off Power (W) from the air flow (kg/s).
+// The effective surface temperature is found from the air side heat transfer (W/K per kJ/kg K)
+func wetCoilOutgoing(Inlet Air, AirMassFlowrate float64, Transfer float64, Power float64) Air {
+	out := Inlet
+	p := Inlet.BarometricPressure()
+	h := Inlet.Enthalpy()
+	ho := h - Power/AirMassFlowrate/1000
+	ntu := Transfer / AirMassFlowrate
+	hs := h - (h-ho)/(1-math.Exp(-ntu))
+	ts := rootOrNearestBound(func(t float64) float64 { return saturationEnthalpy(t, p) - hs }, -100, Inlet.Temperature, 1e-4)
+	out.Temperature = ts + (Inlet.Temperature-ts)*math.Exp(-ntu)
+	out.Humidity = (ho - DryAirHeatCapacity*out.Temperature) / (VaporizationEnthalpyAt0 + WaterSteamHeatCapacity*out.Temperature)
+	out.Humidity = math.Min(math.Min(out.Humidity, Inlet.Humidity), out.SaturationHumidity())
+	return out
+}
+
+// Returns the coil result at the selected air inlet state, water inlet temperature and water flowrate.
+// If the water is below the dew point of the air, the wet coil is rated by the enthalpy method (Braun)
+// and the larger of the dry and wet duties is taken
+func (s WaterCoil) Rate(Inlet Air, VolumetricFlowrate /* m3/h */ float64, Fluid Fluid, WaterInletTemperature /* ºC */ float64, WaterVolumetricFlowrate /* dm3/h */ float64) (out CoilRating, err error) {
+	if err = s.validate(); err != nil {
+		return
+	}
+	if err = Inlet.Validate(); err != nil {
+		return
+	}
+	if VolumetricFlowrate <= 0 || WaterVolumetricFlowrate <= 0 {
+		return out, errors.New("invalid flowrate")
+	}
+	out = CoilRating{
+		Inlet:                   Inlet,
+		Outgoing:                Inlet,
+		VolumetricFlowrate:      VolumetricFlowrate,
+		WaterInletTemperature:   WaterInletTemperature,
+		WaterVolumetricFlowrate: WaterVolumetricFlowrate,
+	}
+	surface := s.surface()
+	ho, efficiency, _, _ := s.airSide(Inlet, VolumetricFlowrate, surface)
+	hi, water_pressure_drop := s.waterSide(Fluid, WaterInletTemperature, WaterVolumetricFlowrate)
+	out.WaterPressureDrop = water_pressure_drop
+	out.PressureDrop = s.PressureDrop(Inlet, VolumetricFlowrate)
+
+	air_mass_flowrate := VolumetricFlowrate * Inlet.AirDensity() / SecondsInHour             // kg/s
+	air_heat_capacity := 1000 * (DryAirHeatCapacity + Inlet.Humidity*WaterSteamHeatCapacity) // J/kg K
+	water_mass_flowrate := WaterVolumetricFlowrate / 1000 / SecondsInHour * Fluid.Density(WaterInletTemperature)
+	water_heat_capacity := 1000 * Fluid.HeatCapacity(WaterInletTemperature)
+	outer := efficiency * ho * surface.outer // W/K
+	inner := hi * surface.inner              // W/K
+
+	// Dry coil
+	ca := air_mass_flowrate * air_heat_capacity
+	cw := water_mass_flowrate * water_heat_capacity
+	cmin, cmax := math.Min(ca, cw), math.Max(ca, cw)
+	power := coilEffectiveness(1/(1/outer+1/inner)/cmin, cmin/cmax, s.Rows) * cmin * (WaterInletTemperature - Inlet.Temperature) // W
+	out.Outgoing.Temperature = Inlet.Temperature + power/ca
+
+	// Wet coil
+	p := Inlet.BarometricPressure()
+	if dew_point := Inlet.DewPoint(); WaterInletTemperature < dew_point {
+		cs := (saturationEnthalpy(dew_point, p) - saturationEnthalpy(WaterInletTemperature, p)) / (dew_point - WaterInletTemperature) * 1000 // J/kg K
+		ca := air_mass_flowrate
+		cw := water_mass_flowrate * water_heat_capacity / cs
+		cmin, cmax := math.Min(ca, cw), math.Max(ca, cw)
+		ua := 1 / (air_heat_capacity/outer + cs/inner) // kg/s
+		h := Inlet.Enthalpy()
+		wet := coilEffectiveness(ua/cmin, cmin/cmax, s.Rows) * cmin * (h - saturationEnthalpy(WaterInletTemperature, p)) * 1000 // W
+		if wet > -power {
+			out.Wet = true
+			power = -wet
+			out.Outgoing = wetCoilOutgoing(Inlet, air_mass_flowrate, outer/air_heat_capacity, wet)
+			out.CondensateMassFlowrate = air_mass_flowrate * (Inlet.Humidity - out.Outgoing.Humidity) * SecondsInHour
+			out.PressureDrop *= WetCoilPressureFactor
+		}
+	}
+	out.Power = power / 1000
+	out.WaterOutgoingTemperature = WaterInletTemperature - power/cw
+	return
+}
+
+// Returns the coil result at the water flowrate that gives the selected outgoing water temperature.
+// The laminar to turbulent transition in the tubes and the wet coil may give the temperatures at several
+// flowrates, the highest one is taken as it is the most powerful
+func (s WaterCoil) RateAtWaterTemperatures(Inlet Air, VolumetricFlowrate /* m3/h */ float64, Fluid Fluid, WaterInletTemperature /* ºC */ float64, WaterOutgoingTemperature /* ºC */ float64) (out CoilRating, err error) {
+	if (WaterInletTemperature-WaterOutgoingTemperature)*(WaterInletTemperature-Inlet.Temperature) <= 0 {
+		return out, errors.New("invalid water temperatures")
+	}
+	flowrate, root_err := highestRoot(func(q float64) float64 {
+		rating, e := s.Rate(Inlet, VolumetricFlowrate, Fluid, WaterInletTemperature, math.Exp(q))
+		if e != nil && err == nil {
+			err = e
+		}
+		return math.Abs(WaterInletTemperature-rating.WaterOutgoingTemperature) - math.Abs(WaterInletTemperature-WaterOutgoingTemperature)
+	}, math.Log(1), math.Log(1e7), 70, 1e-6)
+	if err != nil {
+		return
+	}
+	if root_err != nil {
+		return out, fmt.Errorf("water %v → %v ºC: %w", WaterInletTemperature, WaterOutgoingTemperature, root_err)
+	}
+	return s.Rate(Inlet, VolumetricFlowrate, Fluid, WaterInletTemperature, math.Exp(flowrate))
+}
+
+// Returns the heater (or cooler) result for the task at the selected water temperatures.
+// Capacity is the coil power at the design water temperatures. If it covers the task, the water
+// flowrate is reduced to reach Task.Target exactly (the highest flowrate that does), otherwise LowCapacity is set
+func (s WaterCoil) Select(Task HeaterTask, Fluid Fluid, WaterInletTemperature /* ºC */ float64, WaterOutgoingTemperature /* ºC */ float64) (out HeaterResult, err error) {
+	out = HeaterResult{Inlet: Task.Inlet, Outgoing: Task.Inlet, VolumetricFlowrate: float64(Task.VolumetricFlowrate)}
+	heating := WaterInletTemperature > Task.Inlet.Temperature
+	if Task.VolumetricFlowrate == 0 || (heating && Task.Target <= Task.Inlet.Temperature) || (!heating && Task.Target >= Task.Inlet.Temperature) {
+		return
+	}
+	design, err := s.RateAtWaterTemperatures(Task.Inlet, out.VolumetricFlowrate, Fluid, WaterInletTemperature, WaterOutgoingTemperature)
+	if err != nil {
+		return
+	}
+	out.Capacity = math.Abs(design.Power)
+	rating := design
+	if (heating && design.Outgoing.Temperature < Task.Target) || (!heating && design.Outgoing.Temperature > Task.Target) {
+		out.LowCapacity = true
+	} else {
+		flowrate, root_err := highestRoot(func(q float64) float64 {
+			rating, e := s.Rate(Task.Inlet, out.VolumetricFlowrate, Fluid, WaterInletTemperature, math.Exp(q))
+			if e != nil && err == nil {
+				err = e
+			}
+			return math.Abs(rating.Outgoing.Temperature-Task.Inlet.Temperature) - math.Abs(Task.Target-Task.Inlet.Temperature)
+		}, math.Log(1e-3), math.Log(design.WaterVolumetricFlowrate), 60, 1e-6)
+		if err != nil {
+			return
+		}
+		if root_err != nil {
+			return out, fmt.Errorf("target %v ºC: %w", Task.Target, root_err)
+		}
+		if rating, err = s.Rate(Task.Inlet, out.VolumetricFlowrate, Fluid, WaterInletTemperature, math.Exp(flowrate)); err != nil {
+			return
+		}
+	}
+	out.Outgoing = rating.Outgoing
+	out.Power = math.Abs(rating.Power)
+	out.PressureDrop = rating.PressureDrop
+	out.WaterVolumetricFlowrate = rating.WaterVolumetricFlowrate
+	out.WaterPressureDrop = rating.WaterPressureDrop
+	return
+}
+
+// Returns the heater result for both seasons with the water temperatures and the fluid from Extra.
+// Cooling selects the chilled water temperatures and the cooler antifreeze
+func (s WaterCoil) HeaterResult2(Task HeaterTask2, Extra Extra, Cooling bool) (out HeaterResult2, err error) {
+	out = HeaterResult2{LongName: s.LongName, ShortName: s.ShortName, Length: s.Length}
+	fluid, err := Extra.HeaterFluid()
+	inlet, outgoing := Extra.HeatedWaterInletTemperature, Extra.HeatedWaterOutgoingTemperature
+	if Cooling {
+		fluid, err = Extra.CoolerFluid()
+		inlet, outgoing = Extra.ChilledWaterInletTemperature, Extra.ChilledWaterOutgoingTemperature
+	}
+	if err != nil {
+		return
+	}
+	if out.Summer, err = s.Select(Task.Summer, fluid, inlet, outgoing); err != nil {
+		return
+	}
+	out.Winter, err = s.Select(Task.Winter, fluid, inlet, outgoing)
+	return
+}
@@ -166,6 +166,26 @@ func (s *PrintAir) Air() (out Air) {
 	return
 }
 
+func (s *PrintAir) AirAt(Pressure /* Pa */ float64) (out Air) {
+	out.Temperature = s.Temperature
+	out.Pressure = Pressure
+	out.SetHumidityRatio(s.HumidityRatio)
+	return
+}
+
+// Same as Air, but reports the humidity ratio out of range
+func (s *PrintAir) AirChecked() (Air, error) {
+	return s.AirAtChecked(0)
+}
+
+// Same as AirAt, but reports the humidity ratio out of range
+func (s *PrintAir) AirAtChecked(Pressure /* Pa */ float64) (out Air, err error) {
+	out.Temperature = s.Temperature
+	out.Pressure = Pressure
+	err = out.SetHumidityRatio(s.HumidityRatio)
+	return
+}
+
 func (s *Air) PrintAir(digits int) (out PrintAir) {
 	return PrintAir{math.Round(s.Temperature*math.Pow10(digits)) / math.Pow10(digits), math.Round(s.HumidityRatio()*math.Pow10(digits)) / math.Pow10(digits)}
 }
@@ -349,6 +369,7 @@ func (s UnitResult2) Print(d int) ResultPrint2 {
 	}
 }
 func (s SeasonInitData) Print(d int) TaskPrint {
+	s = s.AtBarometricPressure()
 	return TaskPrint{
 		Outdoor:         s.Outdoor.PrintAir(d),
 		Indoor:          s.Indoor.PrintAir(d),
@@ -1,5 +1,7 @@
 package HVAC
 
+import "math"
+
 type (
 	Extra struct {
 		HeatedWaterInletTemperature     float64
@@ -19,6 +21,12 @@ type (
 		SupplyPressure            uint64
 		ExhaustVolumetricFlowrate uint64
 		ExhaustPressure           uint64
+		BarometricPressure        float64 // Pa. Zero means NormalPressure
+		// Part of SupplyVolumetricFlowrate taken from the room, m3/h
+		RecirculationVolumetricFlowrate uint64
+		// Flowrates are standard (20 ºC, NormalPressure) instead of actual ones.
+		// Actual supply flowrate is at the Outdoor state, actual exhaust flowrate is at the Indoor state
+		StandardFlowrates bool
 	}
 	RequiredComponents struct {
 		SupplyLine          bool
@@ -30,6 +38,7 @@ type (
 		SteamHumidifier     bool
 		MediaHumidifier     bool
 		Dryer               bool
+		Recirculation       bool
 		NoHeater            bool
 		SupplyFilterClasses []string
 		ExhaustFilterClass  []string
@@ -104,6 +113,11 @@ type (
 			Width  uint64
 			Length uint64
 		}
+		// EER of the direct expansion cooler, zero for chilled water
+		CoolerEER struct {
+			Summer float64
+			Winter float64
+		}
 	}
 	UnitSpec struct {
 		Internals PartList
@@ -129,6 +143,8 @@ type (
 		ExhaustPressure      uint64
 		SupplyTotalPressure  uint64
 		ExhaustTotalPressure uint64
+		SupplyMassFlowrate   float64 // kg/h of dry air
+		ExhaustMassFlowrate  float64 // kg/h of dry air
 	}
 	RequestType1 struct {
 		Types map[string]HeaterTask2
@@ -154,17 +170,18 @@ type (
 		ExhaustVolumetricFlowrate uint64
 	}
 	HeatRecoveryResult struct {
-		Supply                Air
-		Exhaust               Air
-		Inside                Air
-		Outside               Air
-		PreHeaterPwr          float64
-		SupplyPressureDrop    float64
-		ExhaustPressureDrop   float64
-		HeatRecovery          float64
-		TemperatureEfficiency float64
-		HumidityEfficiency    float64
-		Freeze                bool
+		Supply                 Air
+		Exhaust                Air
+		Inside                 Air
+		Outside                Air
+		PreHeaterPwr           float64
+		SupplyPressureDrop     float64
+		ExhaustPressureDrop    float64
+		HeatRecovery           float64
+		TemperatureEfficiency  float64
+		HumidityEfficiency     float64
+		Freeze                 bool
+		CondensateMassFlowrate float64 // kg/h, exhaust side
 	}
 	HeatRecoveryTask2 struct {
 		Summer HeatRecoveryTask
@@ -342,6 +359,84 @@ type (
 	}
 )
 
+// Sets the barometric pressure of the season and of all its air states.
+// Humidity (kg/kg) is kept, so relative humidity follows the new pressure
+func (s *SeasonInitData) SetBarometricPressure(Pressure /* Pa */ float64) {
+	s.BarometricPressure = Pressure
+	s.Outdoor.Pressure = Pressure
+	s.Indoor.Pressure = Pressure
+	s.SupplyTarget.Pressure = Pressure
+}
+
+// Returns the season with BarometricPressure set to the air states without their own pressure
+func (s SeasonInitData) AtBarometricPressure() SeasonInitData {
+	for _, state := range []*Air{&s.Outdoor, &s.Indoor, &s.SupplyTarget} {
+		if state.Pressure == 0 {
+			state.Pressure = s.BarometricPressure
+		}
+	}
+	return s
+}
+
+// Returns the state of the outdoor and recirculated air after the mixing box.
+// The recirculation section is not checked here, see UnitTask.MixedAir2 and MixAir
+func (s SeasonInitData) MixedAir() (Mixed Air, Fog float64 /* kg/kg */) {
+	s = s.AtBarometricPressure()
+	recirculation := math.Min(float64(s.RecirculationVolumetricFlowrate), float64(s.SupplyVolumetricFlowrate))
+	return MixAir(s.Outdoor, float64(s.SupplyVolumetricFlowrate)-recirculation, s.Indoor, recirculation)
+}
+
+// Returns the mixed air of both seasons. Without the recirculation section the supply is the outdoor air
+// whatever RecirculationVolumetricFlowrate is set
+func (s UnitTask) MixedAir2() (Summer Air, SummerFog float64, Winter Air, WinterFog float64 /* kg/kg */) {
+	if !s.SectionList.Recirculation {
+		s.Summer.RecirculationVolumetricFlowrate = 0
+		s.Winter.RecirculationVolumetricFlowrate = 0
+	}
+	Summer, SummerFog = s.Summer.MixedAir()
+	Winter, WinterFog = s.Winter.MixedAir()
+	return
+}
+
+// Returns the supply mass flowrate of dry air (kg/h)
+func (s SeasonInitData) SupplyMassFlowrate() float64 {
+	if s.StandardFlowrates {
+		return float64(s.SupplyVolumetricFlowrate) * AirDensity(float64(StandardTemperature))
+	}
+	s = s.AtBarometricPressure()
+	return s.Outdoor.MassFlowrate(float64(s.SupplyVolumetricFlowrate))
+}
+
+// Returns the exhaust mass flowrate of dry air (kg/h)
+func (s SeasonInitData) ExhaustMassFlowrate() float64 {
+	if s.StandardFlowrates {
+		return float64(s.ExhaustVolumetricFlowrate) * AirDensity(float64(StandardTemperature))
+	}
+	s = s.AtBarometricPressure()
+	return s.Indoor.MassFlowrate(float64(s.ExhaustVolumetricFlowrate))
+}
+
+// Sets the mass flowrates of the result from the task
+func (s *UnitResult) SetMassFlowrates(Task SeasonInitData) {
+	s.SupplyMassFlowrate = Task.SupplyMassFlowrate()
+	s.ExhaustMassFlowrate = Task.ExhaustMassFlowrate()
+}
+
+// Returns the actual supply volumetric flowrate (m3/h) at the selected air state, e.g. at a section inlet
+func (s UnitResult) SupplyFlowrateAt(State Air) float64 {
+	return State.VolumetricFlowrate(s.SupplyMassFlowrate)
+}
+
+// Returns the actual exhaust volumetric flowrate (m3/h) at the selected air state, e.g. at a section inlet
+func (s UnitResult) ExhaustFlowrateAt(State Air) float64 {
+	return State.VolumetricFlowrate(s.ExhaustMassFlowrate)
+}
+
+// Sets the barometric pressure of the season from the site altitude
+func (s *SeasonInitData) SetAltitude(Altitude /* m */ float64) {
+	s.SetBarometricPressure(PressureAtAltitude(Altitude))
+}
+
 func (s *PartList) Add(LongName string, ShortName string, qty uint64) {
 	for i := 0; i < len(*s); i++ {
 		if (*s)[i].LongName == LongName {